@@ -6,6 +6,7 @@ toolchain go1.23.6
 
 require (
 	github.com/cli/go-gh/v2 v2.11.2
+	github.com/google/uuid v1.6.0
 	github.com/lithammer/dedent v1.1.0
 	github.com/phsym/console-slog v0.3.1
 	github.com/rhysd/actionlint v1.7.7
@@ -17,6 +18,7 @@ require (
 	github.com/thombashi/gh-taghash v0.4.0
 	github.com/thombashi/go-gitexec v0.1.0
 	golang.org/x/crypto v0.35.0
+	golang.org/x/mod v0.23.0
 	golang.org/x/sync v0.11.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -31,7 +33,6 @@ require (
 	github.com/fatih/color v1.18.0 // indirect
 	github.com/glebarez/go-sqlite v1.22.0 // indirect
 	github.com/glebarez/sqlite v1.11.0 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/henvic/httpretty v0.1.4 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
@@ -1,29 +1,32 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log/slog"
 	"os"
 
-	"github.com/thombashi/eoe"
 	"github.com/thombashi/gh-actionarmor/pkg/cmd"
-	"github.com/thombashi/gh-actionarmor/pkg/git"
 )
 
 func main() {
+	if subcommand := cmd.LookupSubcommand(os.Args[1:]); subcommand != nil {
+		subcommand.Execute()
+		return
+	}
+
 	env, lintErrors := cmd.Execute()
 
-	for _, lerr := range lintErrors {
-		repoID, err := git.GetRepoID(env.GitExecutor, lerr.Project)
-		eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to get the repository ID"))
+	if err := cmd.WriteReport(context.Background(), env, lintErrors); err != nil {
+		env.Logger.Error("failed to write the report", slog.Any("error", err))
+	}
 
-		src, err := os.ReadFile(lerr.WorkflowAbsFilePath)
-		if err != nil {
-			env.Logger.Error("failed to read the workflow file", slog.Any("error", err))
-			continue
+	// the summary table is written only with the text format to keep the other formats parsable
+	if env.Flags.Recursive && cmd.IsTextOutputFormat(env.Flags.OutputFormat) {
+		summaries := cmd.SummarizeByRepository(env.Workflows, lintErrors)
+		if err := cmd.WriteRepositorySummary(os.Stdout, summaries); err != nil {
+			env.Logger.Error("failed to write the summary", slog.Any("error", err))
 		}
-
-		lerr.LintError.Filepath = fmt.Sprintf("%s/%s", repoID, lerr.LintError.Filepath)
-		lerr.LintError.PrettyPrint(os.Stderr, src)
 	}
+
+	env.LogAPIUsage()
 }
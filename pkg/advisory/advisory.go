@@ -0,0 +1,337 @@
+// Package advisory provides an offline database of security advisories for GitHub Actions.
+// The database is loaded from local JSON files in the OSV format (e.g. github/advisory-database)
+// or the format of the GitHub REST API for global security advisories.
+package advisory
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/thombashi/gh-taghash/pkg/resolver"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	osvEcosystem  = "GitHub Actions"
+	ghsaEcosystem = "actions"
+)
+
+// Constraint represents a version constraint: e.g. >= 1.0.0
+type Constraint struct {
+	// Op is a comparison operator: =, >, >=, <, or <=
+	Op string
+
+	// Version is a version to compare with.
+	Version string
+}
+
+// Range represents a range of affected versions. A version is in the range if it satisfies all of the constraints.
+type Range []Constraint
+
+// Affected represents versions of an action that an advisory affects.
+type Affected struct {
+	// Package is a name of the action: OWNER/REPO[/PATH]
+	Package string
+
+	// Versions is a list of affected versions (tags).
+	Versions []string
+
+	// Commits is a list of affected commit hashes.
+	Commits []string
+
+	// Ranges is a list of affected version ranges.
+	Ranges []Range
+}
+
+// Advisory represents a security advisory.
+type Advisory struct {
+	// ID is an ID of the advisory: e.g. GHSA-mrrh-fwg8-r2c3
+	ID string
+
+	// Aliases is a list of other IDs of the advisory: e.g. CVE-2025-30066
+	Aliases []string
+
+	// Summary is a short description of the advisory.
+	Summary string
+
+	// Severity is a severity of the advisory: e.g. high. It may be empty.
+	Severity string
+
+	Affected []Affected
+}
+
+// Database is a collection of advisories indexed by package names.
+type Database struct {
+	advisories map[string][]*Advisory
+	count      int
+}
+
+// NewDatabase creates an empty Database.
+func NewDatabase() *Database {
+	return &Database{
+		advisories: map[string][]*Advisory{},
+	}
+}
+
+func packageKey(name string) string {
+	return strings.ToLower(strings.Trim(name, "/"))
+}
+
+// Add adds an advisory to the database.
+func (d *Database) Add(a *Advisory) {
+	seen := map[string]struct{}{}
+
+	for _, affected := range a.Affected {
+		key := packageKey(affected.Package)
+		if _, exist := seen[key]; exist {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		d.advisories[key] = append(d.advisories[key], a)
+	}
+
+	d.count++
+}
+
+// Len returns the number of advisories in the database.
+func (d Database) Len() int {
+	return d.count
+}
+
+// Load reads advisories from files. A path is either a JSON file or a directory that contains JSON files.
+// A JSON file contains an advisory or an array of advisories.
+func Load(paths ...string) (*Database, error) {
+	db := NewDatabase()
+
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() || (filePath != p && !strings.EqualFold(filepath.Ext(filePath), ".json")) {
+				return nil
+			}
+
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return fmt.Errorf("failed to read an advisory file: %w", err)
+			}
+
+			advisories, err := Parse(data)
+			if err != nil {
+				return fmt.Errorf("%w: path=%s", err, filePath)
+			}
+
+			for _, a := range advisories {
+				db.Add(a)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load advisories: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+// Parse parses the content of an advisory file. Advisories that do not affect GitHub Actions are excluded.
+func Parse(data []byte) ([]*Advisory, error) {
+	var rawMessages []json.RawMessage
+
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &rawMessages); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal advisories: %w", err)
+		}
+	} else {
+		rawMessages = []json.RawMessage{data}
+	}
+
+	advisories := make([]*Advisory, 0, len(rawMessages))
+	for _, raw := range rawMessages {
+		var probe struct {
+			GhsaID string `json:"ghsa_id"`
+		}
+		if err := json.Unmarshal(raw, &probe); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal an advisory: %w", err)
+		}
+
+		var a *Advisory
+		var err error
+		if probe.GhsaID != "" {
+			a, err = parseGHSA(raw)
+		} else {
+			a, err = parseOSV(raw)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(a.Affected) > 0 {
+			advisories = append(advisories, a)
+		}
+	}
+
+	return advisories, nil
+}
+
+// Match returns advisories that affect any of the refs (tags or commit hashes) of an action.
+// names are candidate package names of the action: e.g. OWNER/REPO and OWNER/REPO/PATH
+func (d Database) Match(names []string, refs []string) []*Advisory {
+	matched := make([]*Advisory, 0)
+	seen := map[string]struct{}{}
+
+	for _, name := range names {
+		for _, a := range d.advisories[packageKey(name)] {
+			if _, exist := seen[a.ID]; exist {
+				continue
+			}
+
+			if a.affects(name, refs) {
+				seen[a.ID] = struct{}{}
+				matched = append(matched, a)
+			}
+		}
+	}
+
+	return matched
+}
+
+func (a Advisory) affects(name string, refs []string) bool {
+	for _, affected := range a.Affected {
+		if packageKey(affected.Package) != packageKey(name) {
+			continue
+		}
+
+		if affected.matches(refs) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a Affected) matches(refs []string) bool {
+	versions := make([]string, 0, len(refs))
+
+	for _, ref := range refs {
+		if resolver.IsSHA(ref) {
+			if slices.ContainsFunc(a.Commits, func(commit string) bool {
+				return strings.EqualFold(commit, ref)
+			}) {
+				return true
+			}
+			continue
+		}
+
+		if slices.ContainsFunc(a.Versions, func(version string) bool {
+			return trimVersionPrefix(version) == trimVersionPrefix(ref)
+		}) {
+			return true
+		}
+
+		versions = append(versions, ref)
+	}
+
+	for _, version := range mostSpecificVersions(versions) {
+		for _, r := range a.Ranges {
+			if r.Contains(version) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Contains returns true if a version satisfies all of the constraints of the range.
+func (r Range) Contains(version string) bool {
+	v, ok := canonicalVersion(version)
+	if !ok || len(r) == 0 {
+		return false
+	}
+
+	for _, c := range r {
+		cv, ok := canonicalVersion(c.Version)
+		if !ok {
+			return false
+		}
+
+		cmp := semver.Compare(v, cv)
+
+		var satisfied bool
+		switch c.Op {
+		case "=":
+			satisfied = cmp == 0
+		case ">":
+			satisfied = cmp > 0
+		case ">=":
+			satisfied = cmp >= 0
+		case "<":
+			satisfied = cmp < 0
+		case "<=":
+			satisfied = cmp <= 0
+		}
+
+		if !satisfied {
+			return false
+		}
+	}
+
+	return true
+}
+
+func trimVersionPrefix(version string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(version), "v"), "V")
+}
+
+// canonicalVersion returns a semantic version with the 'v' prefix: e.g. 1.2 -> v1.2
+func canonicalVersion(version string) (string, bool) {
+	v := "v" + trimVersionPrefix(version)
+
+	return v, semver.IsValid(v)
+}
+
+// numVersionComponents returns the number of numeric components of a version: e.g. v4 -> 1, v4.1.0-rc.1 -> 3
+func numVersionComponents(version string) int {
+	core, _, _ := strings.Cut(version, "+")
+	core, _, _ = strings.Cut(core, "-")
+
+	return strings.Count(core, ".") + 1
+}
+
+// mostSpecificVersions returns the versions that have the most numeric components.
+// A floating tag (e.g. v4) points to one of the releases of the major version, which is identified
+// by the more specific tags that point to the same commit (e.g. v4.2.1).
+func mostSpecificVersions(versions []string) []string {
+	maxComponents := 0
+	canonicals := make([]string, 0, len(versions))
+
+	for _, version := range versions {
+		v, ok := canonicalVersion(version)
+		if !ok {
+			continue
+		}
+
+		canonicals = append(canonicals, v)
+		maxComponents = max(maxComponents, numVersionComponents(v))
+	}
+
+	specific := make([]string, 0, len(canonicals))
+	for _, v := range canonicals {
+		if numVersionComponents(v) == maxComponents {
+			specific = append(specific, v)
+		}
+	}
+
+	return specific
+}
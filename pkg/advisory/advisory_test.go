@@ -0,0 +1,192 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const maliciousSHA = "0e58ed8671d6b60d0890c21b07f8835ace038e67"
+
+const testOSVAdvisory = `{
+  "id": "GHSA-mrrh-fwg8-r2c3",
+  "aliases": ["CVE-2025-30066"],
+  "summary": "tj-actions changed-files through 45.0.7 allows remote attackers to discover secrets",
+  "affected": [
+    {
+      "package": {"ecosystem": "GitHub Actions", "name": "tj-actions/changed-files"},
+      "ranges": [
+        {"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "46.0.1"}]},
+        {"type": "GIT", "repo": "https://github.com/tj-actions/changed-files", "events": [{"introduced": "` + maliciousSHA + `"}]}
+      ]
+    },
+    {
+      "package": {"ecosystem": "npm", "name": "changed-files"},
+      "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+    }
+  ],
+  "database_specific": {"severity": "HIGH"}
+}`
+
+const testGHSAAdvisories = `[
+  {
+    "ghsa_id": "GHSA-cxww-7g56-2vh6",
+    "cve_id": "CVE-2024-42471",
+    "summary": "actions/download-artifact has an Arbitrary File Write via artifact extraction",
+    "severity": "high",
+    "vulnerabilities": [
+      {
+        "package": {"ecosystem": "actions", "name": "actions/download-artifact"},
+        "vulnerable_version_range": ">= 4.0.0, < 4.1.7"
+      }
+    ]
+  },
+  {
+    "ghsa_id": "GHSA-xxxx-xxxx-xxxx",
+    "summary": "not an action",
+    "severity": "low",
+    "vulnerabilities": [
+      {
+        "package": {"ecosystem": "pip", "name": "example"},
+        "vulnerable_version_range": "< 1.0.0"
+      }
+    ]
+  }
+]`
+
+func TestParse(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	advisories, err := Parse([]byte(testOSVAdvisory))
+	r.NoError(err)
+	r.Len(advisories, 1)
+	a.Equal("GHSA-mrrh-fwg8-r2c3", advisories[0].ID)
+	a.Equal([]string{"CVE-2025-30066"}, advisories[0].Aliases)
+	a.Equal("high", advisories[0].Severity)
+	r.Len(advisories[0].Affected, 1)
+	a.Equal("tj-actions/changed-files", advisories[0].Affected[0].Package)
+	a.Equal([]string{maliciousSHA}, advisories[0].Affected[0].Commits)
+	a.Equal([]Range{{{Op: "<", Version: "46.0.1"}}}, advisories[0].Affected[0].Ranges)
+
+	advisories, err = Parse([]byte(testGHSAAdvisories))
+	r.NoError(err)
+	r.Len(advisories, 1)
+	a.Equal("GHSA-cxww-7g56-2vh6", advisories[0].ID)
+	a.Equal([]Range{{{Op: ">=", Version: "4.0.0"}, {Op: "<", Version: "4.1.7"}}}, advisories[0].Affected[0].Ranges)
+
+	_, err = Parse([]byte(`{"summary": "no id"}`))
+	a.Error(err)
+}
+
+func TestParseRange(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		value   string
+		version string
+		want    bool
+		wantErr bool
+	}{
+		{value: ">= 4.0.0, < 4.1.7", version: "v4.1.6", want: true},
+		{value: ">= 4.0.0, < 4.1.7", version: "v4.1.7", want: false},
+		{value: "<= 2.0", version: "2.0.0", want: true},
+		{value: "= 1.2.3", version: "v1.2.3", want: true},
+		{value: "> 1.2.3", version: "v1.2.3", want: false},
+		{value: "~ 1.2.3", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		r, err := ParseRange(tc.value)
+		if tc.wantErr {
+			a.Error(err, tc.value)
+			continue
+		}
+
+		a.NoError(err, tc.value)
+		a.Equal(tc.want, r.Contains(tc.version), tc.value)
+	}
+}
+
+func TestDatabase_Match(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	dir := t.TempDir()
+	r.NoError(os.WriteFile(filepath.Join(dir, "osv.json"), []byte(testOSVAdvisory), 0600))
+	r.NoError(os.WriteFile(filepath.Join(dir, "ghsa.json"), []byte(testGHSAAdvisories), 0600))
+	r.NoError(os.WriteFile(filepath.Join(dir, "README.md"), []byte("not an advisory"), 0600))
+
+	db, err := Load(dir)
+	r.NoError(err)
+	a.Equal(2, db.Len())
+
+	testCases := []struct {
+		name  string
+		names []string
+		refs  []string
+		want  []string
+	}{
+		{
+			name:  "affected version",
+			names: []string{"tj-actions/changed-files"},
+			refs:  []string{"v45.0.7"},
+			want:  []string{"GHSA-mrrh-fwg8-r2c3"},
+		},
+		{
+			name:  "fixed version",
+			names: []string{"tj-actions/changed-files"},
+			refs:  []string{"v46.0.1"},
+			want:  []string{},
+		},
+		{
+			name:  "malicious commit",
+			names: []string{"tj-actions/changed-files"},
+			refs:  []string{maliciousSHA},
+			want:  []string{"GHSA-mrrh-fwg8-r2c3"},
+		},
+		{
+			name:  "package name is case-insensitive",
+			names: []string{"Actions/Download-Artifact"},
+			refs:  []string{"v4.1.0"},
+			want:  []string{"GHSA-cxww-7g56-2vh6"},
+		},
+		{
+			name:  "floating tag resolved to a fixed version",
+			names: []string{"actions/download-artifact"},
+			refs:  []string{"v4", "v4.1.8"},
+			want:  []string{},
+		},
+		{
+			name:  "floating tag resolved to an affected version",
+			names: []string{"actions/download-artifact"},
+			refs:  []string{"v4", "v4.1.2"},
+			want:  []string{"GHSA-cxww-7g56-2vh6"},
+		},
+		{
+			name:  "sub-path action",
+			names: []string{"actions/download-artifact/sub", "actions/download-artifact"},
+			refs:  []string{"v4.0.0"},
+			want:  []string{"GHSA-cxww-7g56-2vh6"},
+		},
+		{
+			name:  "unknown action",
+			names: []string{"actions/checkout"},
+			refs:  []string{"v4.0.0"},
+			want:  []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		ids := make([]string, 0)
+		for _, advisory := range db.Match(tc.names, tc.refs) {
+			ids = append(ids, advisory.ID)
+		}
+
+		a.Equal(tc.want, ids, tc.name)
+	}
+}
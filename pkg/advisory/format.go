@@ -0,0 +1,214 @@
+package advisory
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/thombashi/gh-taghash/pkg/resolver"
+)
+
+// osvAdvisory represents an advisory in the OSV format.
+// ref: https://ossf.github.io/osv-schema/
+type osvAdvisory struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Summary  string   `json:"summary"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string              `json:"type"`
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+		Versions []string `json:"versions"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// ghsaAdvisory represents a global security advisory of the GitHub REST API.
+// ref: https://docs.github.com/en/rest/security-advisories/global-advisories
+type ghsaAdvisory struct {
+	GhsaID          string `json:"ghsa_id"`
+	CveID           string `json:"cve_id"`
+	Summary         string `json:"summary"`
+	Severity        string `json:"severity"`
+	Vulnerabilities []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		VulnerableVersionRange string `json:"vulnerable_version_range"`
+	} `json:"vulnerabilities"`
+}
+
+func parseOSV(data []byte) (*Advisory, error) {
+	var osv osvAdvisory
+	if err := json.Unmarshal(data, &osv); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal an OSV advisory: %w", err)
+	}
+
+	if osv.ID == "" {
+		return nil, fmt.Errorf("advisory ID not found")
+	}
+
+	a := &Advisory{
+		ID:       osv.ID,
+		Aliases:  osv.Aliases,
+		Summary:  osv.Summary,
+		Severity: strings.ToLower(osv.DatabaseSpecific.Severity),
+	}
+
+	for _, affected := range osv.Affected {
+		if affected.Package.Ecosystem != osvEcosystem {
+			continue
+		}
+
+		entry := Affected{
+			Package: affected.Package.Name,
+		}
+		for _, version := range affected.Versions {
+			if resolver.IsSHA(version) {
+				entry.Commits = append(entry.Commits, version)
+			} else {
+				entry.Versions = append(entry.Versions, version)
+			}
+		}
+
+		for _, r := range affected.Ranges {
+			if r.Type == "GIT" {
+				// the order of commits is not available offline. only the commits that are known to be affected are matched.
+				for _, event := range r.Events {
+					for _, key := range []string{"introduced", "last_affected"} {
+						if commit := event[key]; resolver.IsSHA(commit) {
+							entry.Commits = append(entry.Commits, commit)
+						}
+					}
+				}
+				continue
+			}
+
+			entry.Ranges = append(entry.Ranges, toOSVRanges(r.Events)...)
+		}
+
+		a.Affected = append(a.Affected, entry)
+	}
+
+	return a, nil
+}
+
+// toOSVRanges converts OSV range events into ranges.
+// An introduced event starts a range and a fixed or last_affected event ends the range.
+func toOSVRanges(events []map[string]string) []Range {
+	ranges := make([]Range, 0)
+	var current Range
+
+	for _, event := range events {
+		if introduced, exist := event["introduced"]; exist {
+			if current != nil {
+				ranges = append(ranges, current)
+			}
+
+			current = Range{}
+			if introduced != "0" {
+				current = append(current, Constraint{Op: ">=", Version: introduced})
+			}
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if fixed, exist := event["fixed"]; exist {
+			ranges = append(ranges, append(current, Constraint{Op: "<", Version: fixed}))
+			current = nil
+		} else if lastAffected, exist := event["last_affected"]; exist {
+			ranges = append(ranges, append(current, Constraint{Op: "<=", Version: lastAffected}))
+			current = nil
+		}
+	}
+
+	if current != nil {
+		if len(current) == 0 {
+			// introduced: 0 without an end: all of the versions are affected
+			current = Range{{Op: ">=", Version: "0"}}
+		}
+		ranges = append(ranges, current)
+	}
+
+	return ranges
+}
+
+func parseGHSA(data []byte) (*Advisory, error) {
+	var ghsa ghsaAdvisory
+	if err := json.Unmarshal(data, &ghsa); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal a GitHub advisory: %w", err)
+	}
+
+	a := &Advisory{
+		ID:       ghsa.GhsaID,
+		Summary:  ghsa.Summary,
+		Severity: strings.ToLower(ghsa.Severity),
+	}
+	if ghsa.CveID != "" {
+		a.Aliases = []string{ghsa.CveID}
+	}
+
+	for _, vuln := range ghsa.Vulnerabilities {
+		if vuln.Package.Ecosystem != ghsaEcosystem {
+			continue
+		}
+
+		r, err := ParseRange(vuln.VulnerableVersionRange)
+		if err != nil {
+			return nil, fmt.Errorf("%w: advisory=%s", err, ghsa.GhsaID)
+		}
+
+		a.Affected = append(a.Affected, Affected{
+			Package: vuln.Package.Name,
+			Ranges:  []Range{r},
+		})
+	}
+
+	return a, nil
+}
+
+// ParseRange parses a comma-separated list of version constraints: e.g. ">= 1.0.0, < 1.2.3"
+func ParseRange(s string) (Range, error) {
+	r := Range{}
+
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		var op string
+		for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+			if strings.HasPrefix(item, candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return nil, fmt.Errorf("invalid version constraint: %s", item)
+		}
+
+		version := strings.TrimSpace(strings.TrimPrefix(item, op))
+		if _, ok := canonicalVersion(version); !ok {
+			return nil, fmt.Errorf("invalid version in a constraint: %s", item)
+		}
+
+		r = append(r, Constraint{Op: op, Version: version})
+	}
+
+	if len(r) == 0 {
+		return nil, fmt.Errorf("empty version range")
+	}
+
+	return r, nil
+}
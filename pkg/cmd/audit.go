@@ -0,0 +1,510 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+)
+
+// AuditLocation represents a location of an action in a workflow.
+type AuditLocation struct {
+	// Path is a path of the workflow file with the repository ID.
+	Path string
+
+	// Line is a line number of the 'uses' value.
+	Line int
+}
+
+func (l AuditLocation) String() string {
+	return fmt.Sprintf("%s:%d", l.Path, l.Line)
+}
+
+// AuditAction represents an action at a ref that is used in a repository.
+type AuditAction struct {
+	// RepoID is a repository ID (OWNER/NAME) of the workflows that use the action.
+	RepoID string
+
+	// Action is an action ID: OWNER/REPO[/PATH].
+	Action string
+
+	// Ref is a ref of the action.
+	Ref string
+
+	// Pinned is true if the action is pinned by a commit hash.
+	Pinned bool
+
+	// CommitHash is a commit hash of the ref.
+	CommitHash string
+
+	// Tags is a list of git tags that point to the commit.
+	Tags []string
+
+	// ArchivedAt is a time when the action repository was archived. It is nil if the repository is not archived.
+	ArchivedAt *time.Time
+
+	// AllowlistReason is a reason why the action is excluded from the rules of trust. It is empty if the action is not allowlisted.
+	AllowlistReason string
+
+	// Locations is a list of locations of the action.
+	Locations []AuditLocation
+
+	// Findings is a list of findings of the action.
+	Findings []*linter.Error
+}
+
+// AuditRepository represents an audit result of a repository.
+type AuditRepository struct {
+	// RepoID is a repository ID (OWNER/NAME).
+	RepoID string
+
+	// NumWorkflows is the number of audited workflow files.
+	NumWorkflows int
+
+	// Actions is a list of the actions used in the repository.
+	Actions []*AuditAction
+
+	// Findings is a list of findings that are not related to an action: e.g. runtime errors.
+	Findings []*linter.Error
+}
+
+// NumFindings returns the number of findings of the repository.
+func (r AuditRepository) NumFindings() int {
+	n := len(r.Findings)
+	for _, action := range r.Actions {
+		n += len(action.Findings)
+	}
+
+	return n
+}
+
+// AuditRule represents a summary of findings of a rule.
+type AuditRule struct {
+	// ID is a rule ID. It is the kind of the findings for findings that are not reported by rules.
+	ID string
+
+	// Description is a description of the rule.
+	Description string
+
+	// NumFindings is the number of findings of the rule.
+	NumFindings map[linter.Severity]int
+
+	// Repositories is a list of repository IDs that have findings of the rule.
+	Repositories []string
+}
+
+// Total returns the total number of findings of the rule.
+func (r AuditRule) Total() int {
+	var n int
+	for _, count := range r.NumFindings {
+		n += count
+	}
+
+	return n
+}
+
+// AuditReport represents an audit report of actions used in workflows.
+type AuditReport struct {
+	// GeneratedAt is a time when the report was generated.
+	GeneratedAt time.Time
+
+	// Repositories is a list of audit results per repository.
+	Repositories []*AuditRepository
+
+	// Rules is a list of the rules that have findings.
+	Rules []*AuditRule
+
+	// HasDetails is true if the tags and the archive dates of the actions were looked up.
+	// They are not looked up if the linter is offline.
+	HasDetails bool
+}
+
+// Allowlisted returns the actions that are allowlisted across the repositories.
+func (r AuditReport) Allowlisted() []*AuditAction {
+	actions := make([]*AuditAction, 0)
+
+	for _, repo := range r.Repositories {
+		for _, action := range repo.Actions {
+			if action.AllowlistReason != "" {
+				actions = append(actions, action)
+			}
+		}
+	}
+
+	return actions
+}
+
+func allowlistReason(params *linter.WorkflowLintParams, action linter.Action) string {
+	if params == nil {
+		return ""
+	}
+
+	trusted, reason := params.IsTrustedAction(action)
+	if !trusted {
+		return ""
+	}
+
+	for _, entry := range params.GetHashAllowlist(action) {
+		if entry.SHA == action.Ref && entry.Comment != nil && strings.TrimSpace(*entry.Comment) != "" {
+			return fmt.Sprintf("%s: %s", reason, strings.TrimSpace(*entry.Comment))
+		}
+	}
+
+	return reason
+}
+
+// BuildAuditReport aggregates the findings by repositories, actions, and rules.
+// The tags and the archive dates of the actions are looked up through the linter unless the linter is offline.
+func BuildAuditReport(ctx context.Context, l linter.Linter, workflows []*WorkflowFindings, numWorkers int64, logger *slog.Logger) *AuditReport {
+	report := &AuditReport{
+		GeneratedAt:  time.Now(),
+		Repositories: []*AuditRepository{},
+		Rules:        []*AuditRule{},
+		HasDetails:   !l.IsOffline(),
+	}
+
+	details := map[string]*linter.ActionDetail{}
+	if report.HasDetails {
+		actions := make([]linter.Action, 0)
+		for _, wf := range workflows {
+			for _, step := range wf.Steps {
+				if step.Usage.Kind == linter.UsesKindAction {
+					actions = append(actions, step.Usage.Action)
+				}
+			}
+		}
+		details = describeActions(ctx, l, actions, numWorkers, logger)
+	}
+
+	repoMap := map[string]*AuditRepository{}
+	actionMap := map[string]*AuditAction{}
+	ruleMap := map[string]*AuditRule{}
+
+	descriptions := map[string]string{}
+	for _, rule := range l.Rules() {
+		descriptions[rule.ID()] = rule.Description()
+	}
+
+	addRuleFindings := func(repoID string, findings []*linter.Error) {
+		for _, lerr := range findings {
+			id := lerr.Title()
+
+			rule, exist := ruleMap[id]
+			if !exist {
+				rule = &AuditRule{
+					ID:           id,
+					Description:  descriptions[lerr.RuleID],
+					NumFindings:  map[linter.Severity]int{},
+					Repositories: []string{},
+				}
+				ruleMap[id] = rule
+				report.Rules = append(report.Rules, rule)
+			}
+
+			rule.NumFindings[lerr.Severity]++
+			if !slices.Contains(rule.Repositories, repoID) {
+				rule.Repositories = append(rule.Repositories, repoID)
+			}
+		}
+	}
+
+	for _, wf := range workflows {
+		repo, exist := repoMap[wf.RepoID]
+		if !exist {
+			repo = &AuditRepository{
+				RepoID:   wf.RepoID,
+				Actions:  []*AuditAction{},
+				Findings: []*linter.Error{},
+			}
+			repoMap[wf.RepoID] = repo
+			report.Repositories = append(report.Repositories, repo)
+		}
+
+		repo.NumWorkflows++
+		repo.Findings = append(repo.Findings, wf.Findings...)
+		addRuleFindings(wf.RepoID, wf.Findings)
+
+		for _, step := range wf.Steps {
+			usage := step.Usage
+			if usage.Kind != linter.UsesKindAction {
+				repo.Findings = append(repo.Findings, step.Findings...)
+				addRuleFindings(wf.RepoID, step.Findings)
+				continue
+			}
+
+			key := wf.RepoID + "\x00" + actionDetailKey(usage.Action)
+			action, exist := actionMap[key]
+			if !exist {
+				action = &AuditAction{
+					RepoID:          wf.RepoID,
+					Action:          usage.Action.ID,
+					Ref:             usage.Action.Ref,
+					Pinned:          usage.Action.IsPinnedBySHA(),
+					Tags:            []string{},
+					AllowlistReason: allowlistReason(wf.Params, usage.Action),
+					Locations:       []AuditLocation{},
+					Findings:        []*linter.Error{},
+				}
+
+				if detail, exist := details[actionDetailKey(usage.Action)]; exist {
+					action.CommitHash = detail.CommitHash
+					action.Tags = detail.Tags
+					if detail.Archived {
+						action.ArchivedAt = detail.ArchivedAt
+					}
+				}
+
+				actionMap[key] = action
+				repo.Actions = append(repo.Actions, action)
+			}
+
+			var line int
+			if usage.Pos != nil {
+				line = usage.Pos.Line
+			}
+
+			action.Locations = append(action.Locations, AuditLocation{Path: wf.DisplayPath, Line: line})
+			action.Findings = append(action.Findings, step.Findings...)
+			addRuleFindings(wf.RepoID, step.Findings)
+		}
+	}
+
+	slices.SortFunc(report.Repositories, func(a, b *AuditRepository) int {
+		return strings.Compare(a.RepoID, b.RepoID)
+	})
+	for _, repo := range report.Repositories {
+		slices.SortFunc(repo.Actions, func(a, b *AuditAction) int {
+			if c := strings.Compare(a.Action, b.Action); c != 0 {
+				return c
+			}
+
+			return strings.Compare(a.Ref, b.Ref)
+		})
+	}
+	slices.SortFunc(report.Rules, func(a, b *AuditRule) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return report
+}
+
+func formatArchivedAt(archivedAt *time.Time) string {
+	if archivedAt == nil {
+		return ""
+	}
+
+	return archivedAt.Format("2006-01-02")
+}
+
+func formatSeverityCounts(counts map[linter.Severity]int) string {
+	return fmt.Sprintf("%d / %d / %d", counts[linter.SeverityError], counts[linter.SeverityWarning], counts[linter.SeverityNotice])
+}
+
+// WriteAuditMarkdown writes an audit report in markdown.
+func WriteAuditMarkdown(w io.Writer, report *AuditReport) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s audit report\n\n", common.ToolName)
+	fmt.Fprintf(&b, "Generated at %s\n\n", report.GeneratedAt.Format(time.RFC3339))
+
+	b.WriteString("## Repositories\n\n")
+	b.WriteString("| Repository | Workflows | Actions | Findings |\n")
+	b.WriteString("|------------|----------:|--------:|---------:|\n")
+	for _, repo := range report.Repositories {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d |\n", escapeMarkdownTableCell(repo.RepoID), repo.NumWorkflows, len(repo.Actions), repo.NumFindings())
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Rules\n\n")
+	if len(report.Rules) == 0 {
+		b.WriteString("No findings.\n\n")
+	} else {
+		b.WriteString("| Rule | Description | Errors / Warnings / Notices | Repositories |\n")
+		b.WriteString("|------|-------------|-----------------------------|--------------|\n")
+		for _, rule := range report.Rules {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				escapeMarkdownTableCell(rule.ID),
+				escapeMarkdownTableCell(rule.Description),
+				formatSeverityCounts(rule.NumFindings),
+				escapeMarkdownTableCell(strings.Join(rule.Repositories, ", ")),
+			)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Allowlisted Actions\n\n")
+	if allowlisted := report.Allowlisted(); len(allowlisted) == 0 {
+		b.WriteString("No allowlisted actions.\n\n")
+	} else {
+		b.WriteString("| Repository | Action | Ref | Reason |\n")
+		b.WriteString("|------------|--------|-----|--------|\n")
+		for _, action := range allowlisted {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				escapeMarkdownTableCell(action.RepoID),
+				escapeMarkdownTableCell(action.Action),
+				escapeMarkdownTableCell(action.Ref),
+				escapeMarkdownTableCell(action.AllowlistReason),
+			)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, repo := range report.Repositories {
+		fmt.Fprintf(&b, "## %s\n\n", repo.RepoID)
+
+		if len(repo.Actions) > 0 {
+			if report.HasDetails {
+				b.WriteString("| Action | Ref | Tags | Archived | Allowlist | Findings | Locations |\n")
+				b.WriteString("|--------|-----|------|----------|-----------|----------|-----------|\n")
+			} else {
+				b.WriteString("| Action | Ref | Allowlist | Findings | Locations |\n")
+				b.WriteString("|--------|-----|-----------|----------|-----------|\n")
+			}
+			for _, action := range repo.Actions {
+				findings := make([]string, 0, len(action.Findings))
+				for _, lerr := range action.Findings {
+					findings = append(findings, fmt.Sprintf("%s %s: %s", lerr.Severity, lerr.Title(), lerr.LintError.Message))
+				}
+
+				locations := make([]string, 0, len(action.Locations))
+				for _, location := range action.Locations {
+					locations = append(locations, location.String())
+				}
+
+				cells := []string{escapeMarkdownTableCell(action.Action), escapeMarkdownTableCell(action.Ref)}
+				if report.HasDetails {
+					cells = append(cells, escapeMarkdownTableCell(strings.Join(action.Tags, ", ")), formatArchivedAt(action.ArchivedAt))
+				}
+				cells = append(cells,
+					escapeMarkdownTableCell(action.AllowlistReason),
+					escapeMarkdownTableCell(strings.Join(findings, "\n")),
+					escapeMarkdownTableCell(strings.Join(locations, "\n")),
+				)
+
+				fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+			}
+			b.WriteString("\n")
+		}
+
+		if len(repo.Findings) > 0 {
+			b.WriteString("Other findings:\n\n")
+			for _, lerr := range repo.Findings {
+				fmt.Fprintf(&b, "- %s:%d: %s %s: %s\n", lerr.DisplayPath(), lerr.LintError.Line, lerr.Severity, lerr.Title(), lerr.LintError.Message)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+var auditHTMLTemplate = template.Must(template.New("audit").Funcs(template.FuncMap{
+	"archivedAt":     formatArchivedAt,
+	"severityCounts": formatSeverityCounts,
+	"join":           strings.Join,
+	"rfc3339":        func(t time.Time) string { return t.Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .ToolName }} audit report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.error { color: #cf222e; }
+.warning { color: #9a6700; }
+.notice { color: #0969da; }
+ul { margin: 0; padding-left: 1.2em; }
+</style>
+</head>
+<body>
+<h1>{{ .ToolName }} audit report</h1>
+<p>Generated at {{ rfc3339 .Report.GeneratedAt }}</p>
+
+<h2>Repositories</h2>
+<table>
+<tr><th>Repository</th><th>Workflows</th><th>Actions</th><th>Findings</th></tr>
+{{- range .Report.Repositories }}
+<tr><td>{{ .RepoID }}</td><td>{{ .NumWorkflows }}</td><td>{{ len .Actions }}</td><td>{{ .NumFindings }}</td></tr>
+{{- end }}
+</table>
+
+<h2>Rules</h2>
+{{- if .Report.Rules }}
+<table>
+<tr><th>Rule</th><th>Description</th><th>Errors / Warnings / Notices</th><th>Repositories</th></tr>
+{{- range .Report.Rules }}
+<tr><td>{{ .ID }}</td><td>{{ .Description }}</td><td>{{ severityCounts .NumFindings }}</td><td>{{ join .Repositories ", " }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>No findings.</p>
+{{- end }}
+
+<h2>Allowlisted Actions</h2>
+{{- with .Report.Allowlisted }}
+<table>
+<tr><th>Repository</th><th>Action</th><th>Ref</th><th>Reason</th></tr>
+{{- range . }}
+<tr><td>{{ .RepoID }}</td><td>{{ .Action }}</td><td>{{ .Ref }}</td><td>{{ .AllowlistReason }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>No allowlisted actions.</p>
+{{- end }}
+
+{{- $hasDetails := .Report.HasDetails }}
+{{- range .Report.Repositories }}
+<h2>{{ .RepoID }}</h2>
+{{- if .Actions }}
+<table>
+<tr><th>Action</th><th>Ref</th>{{ if $hasDetails }}<th>Tags</th><th>Archived</th>{{ end }}<th>Allowlist</th><th>Findings</th><th>Locations</th></tr>
+{{- range .Actions }}
+<tr>
+<td>{{ .Action }}</td>
+<td>{{ .Ref }}</td>
+{{- if $hasDetails }}
+<td>{{ join .Tags ", " }}</td>
+<td>{{ archivedAt .ArchivedAt }}</td>
+{{- end }}
+<td>{{ .AllowlistReason }}</td>
+<td><ul>{{ range .Findings }}<li class="{{ .Severity }}">{{ .Severity }} {{ .Title }}: {{ .LintError.Message }}</li>{{ end }}</ul></td>
+<td><ul>{{ range .Locations }}<li>{{ .String }}</li>{{ end }}</ul></td>
+</tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Findings }}
+<p>Other findings:</p>
+<ul>
+{{- range .Findings }}
+<li class="{{ .Severity }}">{{ .DisplayPath }}:{{ .LintError.Line }}: {{ .Severity }} {{ .Title }}: {{ .LintError.Message }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}
+</body>
+</html>
+`))
+
+// WriteAuditHTML writes an audit report in HTML.
+func WriteAuditHTML(w io.Writer, report *AuditReport) error {
+	return auditHTMLTemplate.Execute(w, struct {
+		ToolName string
+		Report   *AuditReport
+	}{
+		ToolName: common.ToolName,
+		Report:   report,
+	})
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+)
+
+func TestBuildAuditReport_Offline(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// the linter has no clients: actions must not be looked up
+	l, err := linter.New(&linter.Params{Logger: logger, Offline: true})
+	r.NoError(err)
+
+	report := BuildAuditReport(context.Background(), l, newTestWorkflowFindings(t), 2, logger)
+	a.False(report.HasDetails)
+	r.Len(report.Repositories, 1)
+	a.Len(report.Repositories[0].Actions, 2)
+
+	// the columns of the details are omitted
+	var b strings.Builder
+	r.NoError(WriteAuditMarkdown(&b, report))
+	a.Contains(b.String(), "| Action | Ref | Allowlist | Findings | Locations |")
+	a.Contains(b.String(), "| actions/checkout | v4 | official action | error AA001: invalid ref value |")
+	a.NotContains(b.String(), "Tags")
+
+	b.Reset()
+	r.NoError(WriteAuditHTML(&b, report))
+	a.Contains(b.String(), "<tr><th>Action</th><th>Ref</th><th>Allowlist</th><th>Findings</th><th>Locations</th></tr>")
+	a.NotContains(b.String(), "<th>Tags</th>")
+}
+
+func TestWriteAuditMarkdown_Details(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	report := &AuditReport{
+		Repositories: []*AuditRepository{
+			{
+				RepoID: "owner/repo",
+				Actions: []*AuditAction{
+					{Action: "actions/checkout", Ref: "v4", Tags: []string{"v4", "v4.2.2"}},
+				},
+			},
+		},
+		HasDetails: true,
+	}
+
+	var b strings.Builder
+	r.NoError(WriteAuditMarkdown(&b, report))
+	a.Contains(b.String(), "| Action | Ref | Tags | Archived | Allowlist | Findings | Locations |")
+	a.Contains(b.String(), "| actions/checkout | v4 | v4, v4.2.2 |  |  |  |  |")
+
+	b.Reset()
+	r.NoError(WriteAuditHTML(&b, report))
+	a.Contains(b.String(), "<td>v4, v4.2.2</td>")
+}
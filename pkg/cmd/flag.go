@@ -10,7 +10,9 @@ import (
 	"github.com/lithammer/dedent"
 	"github.com/spf13/pflag"
 	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"github.com/thombashi/gh-actionarmor/pkg/git"
 	"github.com/thombashi/gh-actionarmor/pkg/linter"
+	"github.com/thombashi/gh-actionarmor/pkg/workflow"
 )
 
 // flag names: linter
@@ -21,15 +23,28 @@ const (
 	allowArchivedRepoFlagName           = "allow-archived-repo"
 	enforcePinHashFlagName              = "enforce-pin-hash"
 	enforceVerifiedOrganizationFlagName = "enforce-verified-org"
-
-	creatorAllowlistFlagName = "creator-allowlist"
-	actionAllowlistFlagName  = "action-allowlist"
+	requireSignedCommitsFlagName        = "require-signed-commits"
+	minReleaseAgeFlagName               = "min-release-age"
+	maxRepoInactivityFlagName           = "max-repo-inactivity"
+
+	creatorAllowlistFlagName   = "creator-allowlist"
+	actionAllowlistFlagName    = "action-allowlist"
+	deprecatedRuntimesFlagName = "deprecated-runtime"
+	disabledRulesFlagName      = "disable-rule"
 )
 
 type RunFlags struct {
 	ConfigFilePath string
 	LogLevelStr    string
 	NumWorkers     int64
+	RepoID         string
+	Hostname       string
+	StdinFilename  string
+	Rev            string
+	DiffBase       string
+	Recursive      bool
+	Remotes        []string
+	Orgs           []string
 }
 
 type CacheFlags struct {
@@ -45,15 +60,41 @@ type LinterFlags struct {
 	AllowArchivedRepo        bool
 	EnforcePinHash           bool
 	EnforceVerifiedOrg       bool
+	RequireSignedCommits     bool
+	MinReleaseAge            int
+	MaxRepoInactivity        int
+
+	CreatorAllowlist   []string
+	ActionAllowlist    []string
+	DeprecatedRuntimes []string
+	DisabledRules      []string
 
-	CreatorAllowlist []string
-	ActionAllowlist  []string
+	AdvisoryDBPaths []string
+	Offline         bool
+}
+
+type ListFlags struct {
+	Owners        []string
+	Actions       []string
+	GroupByAction bool
+	Format        string
+}
+
+type SbomFlags struct {
+	SbomFormat string
+}
+
+type OutputFlags struct {
+	OutputFormat string
 }
 
 type Flags struct {
 	RunFlags
 	CacheFlags
 	LinterFlags
+	ListFlags
+	SbomFlags
+	OutputFlags
 }
 
 // NamedFlagSet represents a named pflag.FlagSet
@@ -86,12 +127,65 @@ func NewRunFlagSet(flags *Flags) *NamedFlagSet {
 		"info",
 		"log level (debug, info, warn, error)",
 	)
+	flagSet.StringVar(
+		&flags.Rev,
+		"rev",
+		"",
+		"lint workflows and the config file at a git revision (e.g. HEAD~3, refs/pull/1/merge) without checking it out.",
+	)
+	flagSet.StringVar(
+		&flags.DiffBase,
+		"diff-base",
+		"",
+		strings.TrimSpace(dedent.Dedent(`
+			report only findings of 'uses' values that were added or modified relative to a base git revision (e.g. origin/main).
+			if not specified, lint all of the 'uses' values (full-scan mode)`)),
+	)
+	flagSet.BoolVarP(
+		&flags.Recursive,
+		"recursive",
+		"r",
+		false,
+		"find git repositories beneath the directory paths recursively and lint each of them with its own config file. a summary table per repository is written to the standard output.",
+	)
+	flagSet.StringArrayVar(
+		&flags.Remotes,
+		"remote",
+		[]string{},
+		"lint a remote repository (OWNER/REPO[@REF]) through the GitHub API without cloning it.",
+	)
+	flagSet.StringArrayVar(
+		&flags.Orgs,
+		"org",
+		[]string{},
+		"lint all of the non-archived repositories of an organization through the GitHub API without cloning them.",
+	)
+	flagSet.StringVar(
+		&flags.StdinFilename,
+		"stdin-filename",
+		workflow.StdinFilename,
+		"file path of the workflow read from the standard input. used for reporting and finding the config file.",
+	)
 	flagSet.Int64VarP(
 		&flags.NumWorkers,
 		"workers",
 		"n",
 		0,
-		"number of parallel workers. defaults to the number of CPUs in the system.",
+		"number of parallel workers shared by all of the workflows and repositories. defaults to the number of CPUs in the system.",
+	)
+	flagSet.StringVar(
+		&flags.Hostname,
+		"hostname",
+		"",
+		"GitHub host to resolve actions (e.g. github.example.com). if not specified, use the GH_HOST environment variable or github.com.",
+	)
+	flagSet.StringVar(
+		&flags.RepoID,
+		"repo",
+		"",
+		strings.TrimSpace(dedent.Dedent(`
+			repository ID (OWNER/NAME) of the workflows.
+			if not specified, detect from git remotes or the GITHUB_REPOSITORY environment variable`)),
 	)
 
 	return &NamedFlagSet{
@@ -171,6 +265,24 @@ func NewLinterFlagSet(flags *Flags) *NamedFlagSet {
 		linter.DefaultEnforceVerifiedOrg,
 		"enforce using actions from verified organizations",
 	)
+	flagSet.BoolVar(
+		&flags.RequireSignedCommits,
+		requireSignedCommitsFlagName,
+		linter.DefaultRequireSignedCommits,
+		"require commits that actions are pinned to be signed with valid signatures",
+	)
+	flagSet.IntVar(
+		&flags.MinReleaseAge,
+		minReleaseAgeFlagName,
+		linter.DefaultMinReleaseAge,
+		"minimum number of days since the commit or tag that actions refer to was published. 0 to disable",
+	)
+	flagSet.IntVar(
+		&flags.MaxRepoInactivity,
+		maxRepoInactivityFlagName,
+		linter.DefaultMaxRepoInactivity,
+		"warn about actions whose repositories have had no commits or releases for longer than the number of days. 0 to disable",
+	)
 
 	flagSet.StringArrayVar(
 		&flags.CreatorAllowlist,
@@ -184,6 +296,33 @@ func NewLinterFlagSet(flags *Flags) *NamedFlagSet {
 		[]string{},
 		"allowlist of actions (e.g. google-github-actions/auth). if specified, those actions are excluded from the linting.",
 	)
+	flagSet.StringArrayVar(
+		&flags.DeprecatedRuntimes,
+		deprecatedRuntimesFlagName,
+		[]string{},
+		fmt.Sprintf(
+			"deprecated runtimes of actions (runs.using in action.yml). actions running on those runtimes are reported. disabled if not specified. e.g. %s",
+			strings.Join(linter.GitHubDeprecatedRuntimes, ", "),
+		),
+	)
+	flagSet.StringArrayVar(
+		&flags.DisabledRules,
+		disabledRulesFlagName,
+		[]string{},
+		"ID of a rule to disable (e.g. AA005). can be specified multiple times.",
+	)
+	flagSet.StringArrayVar(
+		&flags.AdvisoryDBPaths,
+		"advisory-db",
+		[]string{},
+		"path to a local advisory file or a directory of advisory files (OSV or GitHub advisory JSON). actions affected by the advisories are reported.",
+	)
+	flagSet.BoolVar(
+		&flags.Offline,
+		"offline",
+		false,
+		fmt.Sprintf("lint without network access: only %s and %s are applied, and advisories are matched only by the refs of actions.", linter.RuleIDPinHash, linter.RuleIDVulnerableAction),
+	)
 
 	return &NamedFlagSet{
 		Name:    name,
@@ -191,24 +330,86 @@ func NewLinterFlagSet(flags *Flags) *NamedFlagSet {
 	}
 }
 
-func NewFlags(toolName string, newFlagSetFuncs []NewFlagSetFunc) (*Flags, []string, error) {
-	flags := &Flags{}
+func NewListFlagSet(flags *Flags) *NamedFlagSet {
+	const name = "LIST FLAGS"
+
+	flagSet := pflag.NewFlagSet(name, pflag.ExitOnError)
+
+	flagSet.StringArrayVar(
+		&flags.Owners,
+		"owner",
+		[]string{},
+		"list only actions of the owner (e.g. docker).",
+	)
+	flagSet.StringArrayVar(
+		&flags.Actions,
+		"action",
+		[]string{},
+		"list only the action (e.g. docker/build-push-action).",
+	)
+	flagSet.BoolVar(
+		&flags.GroupByAction,
+		"group-by-action",
+		false,
+		"group the locations by actions and refs.",
+	)
+	flagSet.StringVar(
+		&flags.Format,
+		"format",
+		listFormatTable,
+		fmt.Sprintf("output format (%s)", strings.Join(listFormats, ", ")),
+	)
+
+	return &NamedFlagSet{
+		Name:    name,
+		FlagSet: flagSet,
+	}
+}
+
+func NewOutputFlagSet(flags *Flags) *NamedFlagSet {
+	const name = "OUTPUT FLAGS"
+
+	flagSet := pflag.NewFlagSet(name, pflag.ExitOnError)
+
+	flagSet.StringVar(
+		&flags.OutputFormat,
+		"format",
+		outputFormatText,
+		fmt.Sprintf("output format of findings (%s)", strings.Join(outputFormats, ", ")),
+	)
 
+	return &NamedFlagSet{
+		Name:    name,
+		FlagSet: flagSet,
+	}
+}
+
+func NewSbomFlagSet(flags *Flags) *NamedFlagSet {
+	const name = "SBOM FLAGS"
+
+	flagSet := pflag.NewFlagSet(name, pflag.ExitOnError)
+
+	flagSet.StringVar(
+		&flags.SbomFormat,
+		"format",
+		sbomFormatCycloneDX,
+		fmt.Sprintf("SBOM format (%s)", strings.Join(sbomFormats, ", ")),
+	)
+
+	return &NamedFlagSet{
+		Name:    name,
+		FlagSet: flagSet,
+	}
+}
+
+func parseFlags(usage string, arguments []string, flags *Flags, newFlagSetFuncs []NewFlagSetFunc) ([]string, error) {
 	flagSets := make([]*NamedFlagSet, 0, len(newFlagSetFuncs))
 	for _, f := range newFlagSetFuncs {
 		flagSets = append(flagSets, f(flags))
 	}
 
 	pflag.Usage = func() {
-		msg := fmt.Sprintf(`
-			gh-%s lint actions of 'uses' in GitHub Actions workflows.
-
-			USAGE
-			  gh %s [flags] [path ...]
-			  
-			  A path is either a directory path to a local GitHub repository or the path to a GitHub Actions workflows file.`,
-			toolName, toolName)
-		msg = dedent.Dedent(msg)
+		msg := dedent.Dedent(usage)
 		msg = strings.TrimLeft(msg, "\n")
 		fmt.Fprintln(os.Stderr, msg)
 
@@ -222,16 +423,81 @@ func NewFlags(toolName string, newFlagSetFuncs []NewFlagSetFunc) (*Flags, []stri
 		pflag.CommandLine.AddFlagSet(f.FlagSet)
 	}
 
-	pflag.Parse()
+	if err := pflag.CommandLine.Parse(arguments); err != nil {
+		return nil, err
+	}
 
 	args := pflag.Args()
-	if len(args) == 0 {
+	if len(args) == 0 && len(flags.Remotes) == 0 && len(flags.Orgs) == 0 {
 		args = append(args, ".")
 	}
 
+	if flags.Offline && (len(flags.Remotes) > 0 || len(flags.Orgs) > 0) {
+		return nil, fmt.Errorf("--offline cannot be used with --remote or --org: remote workflows are read through the GitHub API")
+	}
+
 	if flags.NumWorkers <= 0 {
 		flags.NumWorkers = int64(runtime.NumCPU())
 	}
 
+	if flags.RepoID != "" {
+		repoID, err := git.ParseRepoID(flags.RepoID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --repo flag value: %w", err)
+		}
+
+		flags.RepoID = repoID
+	}
+
+	return args, nil
+}
+
+func NewFlags(toolName string, newFlagSetFuncs []NewFlagSetFunc) (*Flags, []string, error) {
+	flags := &Flags{}
+
+	subcommandLines := make([]string, 0, len(subcommands))
+	for _, subcommand := range subcommands {
+		subcommandLines = append(subcommandLines, fmt.Sprintf("\t\t\t  %-12s %s", subcommand.Name, subcommand.Description))
+	}
+
+	usage := fmt.Sprintf(`
+			gh-%s lint actions of 'uses' in GitHub Actions workflows.
+
+			USAGE
+			  gh %s [flags] [path ...]
+			  gh %s <command> [flags] [path ...]
+			  
+			  A path is either a directory path to a local GitHub repository or the path to a GitHub Actions workflows file.
+			  If a path is "-", read a workflow from the standard input.
+			  Precede paths with "--" to lint paths that have the same name as a command: e.g. gh %s -- list
+
+			COMMANDS
+%s`,
+		toolName, toolName, toolName, toolName, strings.Join(subcommandLines, "\n"))
+
+	args, err := parseFlags(usage, os.Args[1:], flags, newFlagSetFuncs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return flags, args, nil
+}
+
+// NewSubcommandFlags parses the flags of a subcommand. os.Args[1:] must start with the subcommand name.
+func NewSubcommandFlags(toolName string, subcommand *Subcommand, newFlagSetFuncs []NewFlagSetFunc) (*Flags, []string, error) {
+	flags := &Flags{}
+
+	usage := fmt.Sprintf(`
+			gh-%s %s: %s
+
+			USAGE
+			  gh %s %s [flags] [path ...]`,
+		toolName, subcommand.Name, subcommand.Description, toolName, subcommand.Name)
+
+	args, err := parseFlags(usage, os.Args[1+subcommand.numArgs():], flags, newFlagSetFuncs)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	return flags, args, nil
 }
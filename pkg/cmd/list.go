@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thombashi/eoe"
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+	"golang.org/x/sync/errgroup"
+)
+
+// output formats of the list subcommand
+const (
+	listFormatTable = "table"
+	listFormatCSV   = "csv"
+	listFormatJSON  = "json"
+)
+
+var listFormats = []string{listFormatTable, listFormatCSV, listFormatJSON}
+
+// ActionRefInfo represents information of an action at a ref.
+type ActionRefInfo struct {
+	Action     string     `json:"action"`
+	Owner      string     `json:"owner"`
+	Name       string     `json:"name"`
+	SubPath    string     `json:"sub_path"`
+	Ref        string     `json:"ref"`
+	Pinned     bool       `json:"pinned"`
+	CommitHash string     `json:"commit_hash,omitempty"`
+	Tags       []string   `json:"tags"`
+	Archived   bool       `json:"archived"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+}
+
+// ActionLocation represents a location of an action in a workflow.
+type ActionLocation struct {
+	Repo     string `json:"repo"`
+	Rev      string `json:"rev,omitempty"`
+	Workflow string `json:"workflow"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Job      string `json:"job"`
+	Step     string `json:"step,omitempty"`
+
+	displayPath string
+}
+
+func (l ActionLocation) String() string {
+	return fmt.Sprintf("%s:%d:%d", l.displayPath, l.Line, l.Column)
+}
+
+// ActionListEntry represents an action used at a location.
+type ActionListEntry struct {
+	ActionRefInfo
+	ActionLocation
+}
+
+// ActionListGroup represents an action at a ref with the locations where it is used.
+type ActionListGroup struct {
+	ActionRefInfo
+	Locations []ActionLocation `json:"locations"`
+}
+
+func matchesAny(value string, patterns []string) bool {
+	return slices.ContainsFunc(patterns, func(p string) bool {
+		return strings.EqualFold(value, p)
+	})
+}
+
+// FilterActionUsages returns the action usages that match the owners and the actions.
+// Empty owners or actions match all of the usages.
+func FilterActionUsages(usages []*linter.ActionUsage, owners, actions []string) []*linter.ActionUsage {
+	filtered := make([]*linter.ActionUsage, 0, len(usages))
+
+	for _, usage := range usages {
+		if len(owners) > 0 && !matchesAny(usage.Action.Owner, owners) {
+			continue
+		}
+
+		if len(actions) > 0 && !matchesAny(usage.Action.RepoID(), actions) && !matchesAny(usage.Action.ID, actions) {
+			continue
+		}
+
+		filtered = append(filtered, usage)
+	}
+
+	return filtered
+}
+
+func readWorkflowContent(wfLintInfo linter.WorkflowLintInfo) ([]byte, error) {
+	if wfLintInfo.Content != nil {
+		return wfLintInfo.Content, nil
+	}
+
+	content, err := os.ReadFile(wfLintInfo.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read a workflow file: path=%s, error=%w", wfLintInfo.FilePath, err)
+	}
+
+	return content, nil
+}
+
+func listActionUsages(wfLintInfoList []linter.WorkflowLintInfo, logger *slog.Logger) ([]*linter.ActionUsage, error) {
+	usages := make([]*linter.ActionUsage, 0)
+
+	for _, wfLintInfo := range wfLintInfoList {
+		content, err := readWorkflowContent(wfLintInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		l, err := linter.ListActionUsages(wfLintInfo, content)
+		if err != nil {
+			logger.Error("failed to list actions", slog.Any("error", err))
+			continue
+		}
+
+		usages = append(usages, l...)
+	}
+
+	return usages, nil
+}
+
+func actionDetailKey(a linter.Action) string {
+	return fmt.Sprintf("%s/%s@%s", a.Host, a.RepoID(), a.Ref)
+}
+
+// describeActions returns a mapping of actions to their details.
+// Actions that failed to be described are logged and excluded from the mapping.
+// No actions are described if the linter is offline.
+func describeActions(ctx context.Context, l linter.Linter, actions []linter.Action, numWorkers int64, logger *slog.Logger) map[string]*linter.ActionDetail {
+	var mu sync.Mutex
+	details := make(map[string]*linter.ActionDetail)
+
+	if l.IsOffline() {
+		logger.Debug("skip describing actions", slog.String("reason", "offline"))
+		return details
+	}
+
+	if err := l.PrefetchContext(ctx, actions); err != nil {
+		logger.Warn("failed to prefetch metadata of actions", slog.Any("error", err))
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(int(numWorkers))
+
+	seen := make(map[string]struct{})
+	for _, action := range actions {
+		key := actionDetailKey(action)
+		if _, exist := seen[key]; exist {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		eg.Go(func() error {
+			detail, err := l.DescribeActionContext(ctx, action)
+			if err != nil {
+				logger.Warn("failed to describe an action", slog.String("action", action.String()), slog.Any("error", err))
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			details[key] = detail
+
+			return nil
+		})
+	}
+
+	_ = eg.Wait()
+
+	return details
+}
+
+// NewActionListEntries creates a list of ActionListEntry from the action usages and the details of the actions.
+func NewActionListEntries(usages []*linter.ActionUsage, details map[string]*linter.ActionDetail) []*ActionListEntry {
+	entries := make([]*ActionListEntry, 0, len(usages))
+
+	for _, usage := range usages {
+		action := usage.Action
+		refInfo := ActionRefInfo{
+			Action:  action.ID,
+			Owner:   action.Owner,
+			Name:    action.Name,
+			SubPath: action.SubPath(),
+			Ref:     action.Ref,
+			Pinned:  action.IsPinnedBySHA(),
+			Tags:    []string{},
+		}
+
+		if detail, exist := details[actionDetailKey(action)]; exist {
+			refInfo.CommitHash = detail.CommitHash
+			refInfo.Tags = detail.Tags
+			refInfo.Archived = detail.Archived
+			refInfo.ArchivedAt = detail.ArchivedAt
+		}
+
+		entries = append(entries, &ActionListEntry{
+			ActionRefInfo: refInfo,
+			ActionLocation: ActionLocation{
+				Repo:        usage.RepoID,
+				Rev:         usage.Rev,
+				Workflow:    usage.FilePath,
+				Line:        usage.Pos.Line,
+				Column:      usage.Pos.Col,
+				Job:         usage.JobID,
+				Step:        usage.Step(),
+				displayPath: usage.DisplayPath(),
+			},
+		})
+	}
+
+	return entries
+}
+
+// GroupActionListEntries groups the entries by actions and refs.
+func GroupActionListEntries(entries []*ActionListEntry) []*ActionListGroup {
+	groupMap := make(map[string]*ActionListGroup)
+
+	for _, entry := range entries {
+		key := entry.Action + "@" + entry.Ref
+
+		group, exist := groupMap[key]
+		if !exist {
+			group = &ActionListGroup{
+				ActionRefInfo: entry.ActionRefInfo,
+				Locations:     make([]ActionLocation, 0),
+			}
+			groupMap[key] = group
+		}
+
+		group.Locations = append(group.Locations, entry.ActionLocation)
+	}
+
+	groups := make([]*ActionListGroup, 0, len(groupMap))
+	for _, group := range groupMap {
+		groups = append(groups, group)
+	}
+
+	slices.SortFunc(groups, func(a, b *ActionListGroup) int {
+		if c := strings.Compare(a.Action, b.Action); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Ref, b.Ref)
+	})
+
+	return groups
+}
+
+func (i ActionRefInfo) archivedString() string {
+	if !i.Archived {
+		return "false"
+	}
+
+	if i.ArchivedAt == nil {
+		return "true"
+	}
+
+	return fmt.Sprintf("true (%s)", i.ArchivedAt.Format("2006-01-02"))
+}
+
+func (i ActionRefInfo) row() []string {
+	return []string{
+		i.Action,
+		i.Owner,
+		i.Name,
+		i.SubPath,
+		i.Ref,
+		strconv.FormatBool(i.Pinned),
+		strings.Join(i.Tags, ","),
+		i.archivedString(),
+	}
+}
+
+var actionRefInfoHeader = []string{"ACTION", "OWNER", "NAME", "SUB-PATH", "REF", "PINNED", "TAGS", "ARCHIVED"}
+
+func (l ActionLocation) row() []string {
+	return []string{l.String(), l.Job, l.Step}
+}
+
+var actionLocationHeader = []string{"LOCATION", "JOB", "STEP"}
+
+func (g ActionListGroup) row() []string {
+	locations := make([]string, 0, len(g.Locations))
+	for _, loc := range g.Locations {
+		locations = append(locations, loc.String())
+	}
+
+	return append(g.ActionRefInfo.row(), strconv.Itoa(len(g.Locations)), strings.Join(locations, " "))
+}
+
+var actionListGroupHeader = append(slices.Clone(actionRefInfoHeader), "COUNT", "LOCATIONS")
+
+func writeRows(w io.Writer, format string, header []string, rows [][]string) error {
+	switch format {
+	case listFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+
+		return cw.Error()
+
+	case listFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+
+		return tw.Flush()
+	}
+
+	return fmt.Errorf("unknown format: %s", format)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
+
+// WriteActionList writes the entries in the format.
+func WriteActionList(w io.Writer, format string, entries []*ActionListEntry) error {
+	if format == listFormatJSON {
+		return writeJSON(w, entries)
+	}
+
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, append(entry.ActionRefInfo.row(), entry.ActionLocation.row()...))
+	}
+
+	return writeRows(w, format, append(slices.Clone(actionRefInfoHeader), actionLocationHeader...), rows)
+}
+
+// WriteActionListGroups writes the groups in the format.
+func WriteActionListGroups(w io.Writer, format string, groups []*ActionListGroup) error {
+	if format == listFormatJSON {
+		return writeJSON(w, groups)
+	}
+
+	rows := make([][]string, 0, len(groups))
+	for _, group := range groups {
+		rows = append(rows, group.row())
+	}
+
+	return writeRows(w, format, actionListGroupHeader, rows)
+}
+
+// ExecuteList executes the list subcommand.
+func ExecuteList(subcommand *Subcommand) {
+	flags, args, err := NewSubcommandFlags(common.ToolName, subcommand, []NewFlagSetFunc{
+		NewRunFlagSet,
+		NewCacheFlagSet,
+		NewListFlagSet,
+	})
+	eoe.ExitOnError(err, eoe.NewParams().WithMessage("failed to set flags"))
+
+	if !slices.Contains(listFormats, flags.Format) {
+		eoe.ExitOnError(
+			fmt.Errorf("expected=%s, actual=%s", strings.Join(listFormats, "|"), flags.Format),
+			eoe.NewParams().WithMessage("invalid --format flag value"),
+		)
+	}
+
+	ctx := context.Background()
+	env := prepare(ctx, flags, args)
+	defer env.LogAPIUsage()
+
+	usages, err := listActionUsages(env.Workflows, env.Logger)
+	eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to list actions"))
+
+	usages = FilterActionUsages(usages, flags.Owners, flags.Actions)
+	actions := make([]linter.Action, 0, len(usages))
+	for _, usage := range usages {
+		actions = append(actions, usage.Action)
+	}
+
+	details := describeActions(ctx, env.Linter, actions, flags.NumWorkers, env.Logger)
+	entries := NewActionListEntries(usages, details)
+
+	if flags.GroupByAction {
+		err = WriteActionListGroups(os.Stdout, flags.Format, GroupActionListEntries(entries))
+	} else {
+		err = WriteActionList(os.Stdout, flags.Format, entries)
+	}
+	eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to write the action list"))
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/thombashi/eoe"
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+	"github.com/thombashi/gh-actionarmor/pkg/workflow"
+	"golang.org/x/sync/errgroup"
+)
+
+// lockTarget represents a repository to update the lockfile.
+type lockTarget struct {
+	rootDir   string
+	lockfile  *workflow.Lockfile
+	workflows []linter.WorkflowLintInfo
+}
+
+// groupLockTargets groups the workflows by repositories.
+// Workflows that are not in a working tree are skipped since the lockfile cannot be written.
+func groupLockTargets(wfLintInfoList []linter.WorkflowLintInfo, logger *slog.Logger) []*lockTarget {
+	targetMap := make(map[string]*lockTarget)
+
+	for _, wfLintInfo := range wfLintInfoList {
+		if wfLintInfo.Project == nil || wfLintInfo.Rev != "" {
+			logger.Warn("skip a workflow that is not in a working tree", slog.String("path", wfLintInfo.FilePath))
+			continue
+		}
+
+		rootDir := wfLintInfo.Project.RootDir()
+		target, exist := targetMap[rootDir]
+		if !exist {
+			target = &lockTarget{
+				rootDir:  rootDir,
+				lockfile: wfLintInfo.Lockfile,
+			}
+			targetMap[rootDir] = target
+		}
+
+		// lock all of the tag references regardless of the --diff-base flag
+		wfLintInfo.TargetUses = nil
+		target.workflows = append(target.workflows, wfLintInfo)
+	}
+
+	targets := make([]*lockTarget, 0, len(targetMap))
+	for _, target := range targetMap {
+		targets = append(targets, target)
+	}
+
+	slices.SortFunc(targets, func(a, b *lockTarget) int {
+		return strings.Compare(a.rootDir, b.rootDir)
+	})
+
+	return targets
+}
+
+// updateLockfile resolves the tag references of the actions used in the workflows and returns a new lockfile.
+// If a tag reference failed to be resolved, the entry of the current lockfile is kept.
+// Actions of an unknown host are recorded with the default host.
+func updateLockfile(ctx context.Context, l linter.Linter, target *lockTarget, defaultHost string, numWorkers int64, logger *slog.Logger) (*workflow.Lockfile, error) {
+	usages, err := listActionUsages(target.workflows, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	lockfile := workflow.NewLockfile()
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(int(numWorkers))
+
+	seen := make(map[string]struct{})
+	for _, usage := range usages {
+		action := usage.Action
+		if action.IsPinnedBySHA() {
+			continue
+		}
+
+		host := action.HostOr(defaultHost)
+		key := workflow.LockKey(host, action.RepoID(), action.Ref)
+		if _, exist := seen[key]; exist {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		eg.Go(func() error {
+			sha, err := l.ResolveTagContext(ctx, action)
+			if err != nil {
+				logger.Warn("failed to resolve a tag", slog.String("action", action.String()), slog.Any("error", err))
+
+				if target.lockfile == nil {
+					return nil
+				}
+
+				locked, exist := target.lockfile.Lookup(host, action.RepoID(), action.Ref)
+				if !exist {
+					return nil
+				}
+
+				sha = locked
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			lockfile.Set(host, action.RepoID(), action.Ref, sha)
+
+			return nil
+		})
+	}
+
+	_ = eg.Wait()
+
+	return lockfile, nil
+}
+
+// WriteLockfileChanges writes changes of a lockfile.
+func WriteLockfileChanges(w io.Writer, filePath string, changes []workflow.LockfileChange) {
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "%s: up to date\n", filePath)
+		return
+	}
+
+	fmt.Fprintf(w, "%s: %d changes\n", filePath, len(changes))
+
+	for _, change := range changes {
+		switch {
+		case change.Old == "":
+			fmt.Fprintf(w, "  + %s %s\n", change.Key, change.New)
+		case change.New == "":
+			fmt.Fprintf(w, "  - %s %s\n", change.Key, change.Old)
+		default:
+			fmt.Fprintf(w, "  ~ %s %s -> %s\n", change.Key, change.Old, change.New)
+		}
+	}
+}
+
+// ExecuteLockUpdate executes the lock update subcommand.
+func ExecuteLockUpdate(subcommand *Subcommand) {
+	flags, args, err := NewSubcommandFlags(common.ToolName, subcommand, []NewFlagSetFunc{
+		NewRunFlagSet,
+		NewCacheFlagSet,
+	})
+	eoe.ExitOnError(err, eoe.NewParams().WithMessage("failed to set flags"))
+
+	ctx := context.Background()
+	env := prepare(ctx, flags, args)
+	defer env.LogAPIUsage()
+
+	for _, target := range groupLockTargets(env.Workflows, env.Logger) {
+		lockfile, err := updateLockfile(ctx, env.Linter, target, env.Host, flags.NumWorkers, env.Logger)
+		eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to update the lockfile"))
+
+		filePath := filepath.Join(target.rootDir, filepath.FromSlash(workflow.LockfilePath))
+		changes := lockfile.Diff(target.lockfile)
+		WriteLockfileChanges(os.Stdout, filePath, changes)
+
+		if len(changes) == 0 && target.lockfile != nil {
+			continue
+		}
+
+		err = lockfile.WriteFile(target.rootDir)
+		eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to write the lockfile"))
+	}
+}
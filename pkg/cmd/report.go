@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+)
+
+// output formats of findings
+const (
+	outputFormatText       = "text"
+	outputFormatGitHub     = "github"
+	outputFormatJUnit      = "junit"
+	outputFormatCheckstyle = "checkstyle"
+	outputFormatMarkdown   = "markdown"
+	outputFormatHTML       = "html"
+)
+
+var outputFormats = []string{
+	outputFormatText,
+	outputFormatGitHub,
+	outputFormatJUnit,
+	outputFormatCheckstyle,
+	outputFormatMarkdown,
+	outputFormatHTML,
+}
+
+// IsTextOutputFormat returns true if the output format is the human-readable text format.
+func IsTextOutputFormat(format string) bool {
+	return format == outputFormatText
+}
+
+const envGitHubStepSummary = "GITHUB_STEP_SUMMARY"
+
+// WriteReport writes the findings in the output format of the flags.
+func WriteReport(ctx context.Context, env *Environment, lintErrors []*linter.Error) error {
+	switch env.Flags.OutputFormat {
+	case outputFormatGitHub:
+		if err := WriteGitHubAnnotations(os.Stdout, lintErrors); err != nil {
+			return fmt.Errorf("failed to write annotations: %w", err)
+		}
+
+		summaryPath := os.Getenv(envGitHubStepSummary)
+		if summaryPath == "" {
+			env.Logger.Debug("skip writing a step summary", slog.String("reason", envGitHubStepSummary+" is not set"))
+			return nil
+		}
+
+		f, err := os.OpenFile(summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open the step summary file: %w", err)
+		}
+		defer f.Close()
+
+		if err := WriteGitHubStepSummary(f, lintErrors); err != nil {
+			return fmt.Errorf("failed to write the step summary: %w", err)
+		}
+
+		return nil
+
+	case outputFormatJUnit:
+		return WriteJUnitReport(os.Stdout, GroupFindingsByStep(env.Workflows, lintErrors, env.Logger))
+
+	case outputFormatCheckstyle:
+		return WriteCheckstyleReport(os.Stdout, GroupFindingsByStep(env.Workflows, lintErrors, env.Logger))
+
+	case outputFormatMarkdown, outputFormatHTML:
+		workflows := GroupFindingsByStep(env.Workflows, lintErrors, env.Logger)
+		report := BuildAuditReport(ctx, env.Linter, workflows, env.Flags.NumWorkers, env.Logger)
+
+		if env.Flags.OutputFormat == outputFormatHTML {
+			return WriteAuditHTML(os.Stdout, report)
+		}
+
+		return WriteAuditMarkdown(os.Stdout, report)
+
+	default:
+		writeTextReport(os.Stderr, lintErrors, env.Logger)
+		return nil
+	}
+}
+
+// StepFindings represents findings of a 'uses' step of a workflow.
+type StepFindings struct {
+	// Usage is the 'uses' value of the step.
+	Usage *linter.ActionUsage
+
+	// Findings is a list of findings of the step. It is empty if the step passed the linting.
+	Findings []*linter.Error
+}
+
+// WorkflowFindings represents findings of a workflow file.
+type WorkflowFindings struct {
+	// RepoID is a repository ID (OWNER/NAME) of the project that contains the workflow file.
+	RepoID string
+
+	// Params is a set of lint parameters of the workflow. It is nil for workflows that are not in the linted workflows.
+	Params *linter.WorkflowLintParams
+
+	// DisplayPath is a path of the workflow file with the repository ID: OWNER/NAME/PATH or OWNER/NAME@REV:PATH.
+	DisplayPath string
+
+	// RelPath is a path of the workflow file relative to the project root.
+	RelPath string
+
+	// Steps is a list of 'uses' steps in the order of their positions.
+	Steps []*StepFindings
+
+	// Findings is a list of findings that are not related to a 'uses' step: e.g. runtime errors.
+	Findings []*linter.Error
+}
+
+// NumFindings returns the number of findings of the workflow, including the findings of the steps.
+func (wf WorkflowFindings) NumFindings() int {
+	n := len(wf.Findings)
+	for _, step := range wf.Steps {
+		n += len(step.Findings)
+	}
+
+	return n
+}
+
+// workflowDisplayPath returns a path of the workflow file in the same format as linter.Error.DisplayPath.
+func workflowDisplayPath(wfLintInfo linter.WorkflowLintInfo, relPath string) string {
+	return linter.FormatDisplayPath(wfLintInfo.RepoID, wfLintInfo.Rev, relPath)
+}
+
+func workflowKey(repoID, rev, filePath string) string {
+	return strings.Join([]string{repoID, rev, filePath}, "\x00")
+}
+
+// GroupFindingsByStep groups findings by workflow files and 'uses' steps.
+// Workflows are in the order of wfLintInfoList, and findings are assigned to the steps at the same lines.
+func GroupFindingsByStep(wfLintInfoList []linter.WorkflowLintInfo, lintErrors []*linter.Error, logger *slog.Logger) []*WorkflowFindings {
+	workflows := make([]*WorkflowFindings, 0, len(wfLintInfoList))
+	workflowMap := map[string]*WorkflowFindings{}
+
+	for _, wfLintInfo := range wfLintInfoList {
+		relPath, err := wfLintInfo.RelPath()
+		if err != nil {
+			relPath = wfLintInfo.FilePath
+		}
+
+		wf := &WorkflowFindings{
+			RepoID:      wfLintInfo.RepoID,
+			Params:      wfLintInfo.Params,
+			DisplayPath: workflowDisplayPath(wfLintInfo, relPath),
+			RelPath:     relPath,
+			Steps:       []*StepFindings{},
+			Findings:    []*linter.Error{},
+		}
+
+		content, err := readWorkflowContent(wfLintInfo)
+		if err == nil {
+			var usages []*linter.ActionUsage
+
+			usages, err = linter.ListUsages(wfLintInfo, content)
+			for _, usage := range usages {
+				if usage.StepIndex < 0 {
+					continue
+				}
+
+				wf.Steps = append(wf.Steps, &StepFindings{Usage: usage, Findings: []*linter.Error{}})
+			}
+		}
+		if err != nil {
+			logger.Error("failed to list 'uses' steps", slog.String("path", wfLintInfo.FilePath), slog.Any("error", err))
+		}
+
+		key := workflowKey(wfLintInfo.RepoID, wfLintInfo.Rev, wfLintInfo.FilePath)
+		if _, exist := workflowMap[key]; !exist {
+			workflowMap[key] = wf
+			workflows = append(workflows, wf)
+		}
+	}
+
+	for _, lerr := range lintErrors {
+		key := workflowKey(lerr.RepoID, lerr.Rev, lerr.WorkflowAbsFilePath)
+		wf, exist := workflowMap[key]
+		if !exist {
+			wf = &WorkflowFindings{
+				RepoID:      lerr.RepoID,
+				DisplayPath: lerr.DisplayPath(),
+				RelPath:     lerr.LintError.Filepath,
+				Steps:       []*StepFindings{},
+				Findings:    []*linter.Error{},
+			}
+			workflowMap[key] = wf
+			workflows = append(workflows, wf)
+		}
+
+		idx := slices.IndexFunc(wf.Steps, func(step *StepFindings) bool {
+			return step.Usage.Pos != nil && step.Usage.Pos.Line == lerr.LintError.Line
+		})
+		if idx < 0 {
+			wf.Findings = append(wf.Findings, lerr)
+			continue
+		}
+
+		wf.Steps[idx].Findings = append(wf.Steps[idx].Findings, lerr)
+	}
+
+	return workflows
+}
+
+func writeTextReport(w io.Writer, lintErrors []*linter.Error, logger *slog.Logger) {
+	for _, lerr := range lintErrors {
+		src := lerr.Source
+		if src == nil {
+			var err error
+
+			src, err = os.ReadFile(lerr.WorkflowAbsFilePath)
+			if err != nil {
+				logger.Error("failed to read the workflow file", slog.Any("error", err))
+				continue
+			}
+		}
+
+		lerr.LintError.Filepath = lerr.DisplayPath()
+		lerr.LintError.Kind = lerr.DisplayKind()
+		lerr.LintError.PrettyPrint(w, src)
+	}
+}
+
+// escapeWorkflowCommandData escapes a message of a workflow command.
+func escapeWorkflowCommandData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeWorkflowCommandProperty escapes a property value of a workflow command.
+func escapeWorkflowCommandProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// WriteGitHubAnnotations writes the findings as workflow commands of GitHub Actions:
+// ::SEVERITY file=PATH,line=LINE,col=COL,title=RULE::MESSAGE
+// ref: https://docs.github.com/en/actions/writing-workflows/choosing-what-your-workflow-does/workflow-commands-for-github-actions
+func WriteGitHubAnnotations(w io.Writer, lintErrors []*linter.Error) error {
+	for _, lerr := range lintErrors {
+		props := []string{
+			"file=" + escapeWorkflowCommandProperty(lerr.LintError.Filepath),
+		}
+		if lerr.LintError.Line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", lerr.LintError.Line))
+		}
+		if lerr.LintError.Column > 0 {
+			props = append(props, fmt.Sprintf("col=%d", lerr.LintError.Column))
+		}
+		props = append(props, "title="+escapeWorkflowCommandProperty(lerr.Title()))
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n",
+			lerr.Severity, strings.Join(props, ","), escapeWorkflowCommandData(lerr.LintError.Message),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// escapeMarkdownTableCell escapes a value of a markdown table cell.
+func escapeMarkdownTableCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\r", "", "\n", "<br>").Replace(s)
+}
+
+// WriteGitHubStepSummary writes a markdown summary table of the findings for a job summary of GitHub Actions.
+func WriteGitHubStepSummary(w io.Writer, lintErrors []*linter.Error) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s\n\n", common.ToolName)
+
+	if len(lintErrors) == 0 {
+		b.WriteString("No findings.\n\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	counts := map[linter.Severity]int{}
+	for _, lerr := range lintErrors {
+		counts[lerr.Severity]++
+	}
+	fmt.Fprintf(&b, "%d findings: %d errors, %d warnings, %d notices\n\n",
+		len(lintErrors), counts[linter.SeverityError], counts[linter.SeverityWarning], counts[linter.SeverityNotice])
+
+	b.WriteString("| Severity | Rule | File | Line | Message |\n")
+	b.WriteString("|----------|------|------|-----:|---------|\n")
+
+	for _, lerr := range lintErrors {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n",
+			lerr.Severity,
+			escapeMarkdownTableCell(lerr.Title()),
+			escapeMarkdownTableCell(lerr.DisplayPath()),
+			lerr.LintError.Line,
+			escapeMarkdownTableCell(lerr.LintError.Message),
+		)
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
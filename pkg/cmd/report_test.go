@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lithammer/dedent"
+	"github.com/rhysd/actionlint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+)
+
+func TestEscapeWorkflowCommandData(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "no special characters",
+			value: "invalid ref value",
+			want:  "invalid ref value",
+		},
+		{
+			name:  "percent",
+			value: "100%",
+			want:  "100%25",
+		},
+		{
+			name:  "newlines",
+			value: "line1\r\nline2\nline3",
+			want:  "line1%0D%0Aline2%0Aline3",
+		},
+		{
+			name:  "colons and commas are not escaped in data",
+			value: "action=a/b, ref=v1: x",
+			want:  "action=a/b, ref=v1: x",
+		},
+		{
+			name:  "escaped sequence",
+			value: "%0A",
+			want:  "%250A",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			a.Equal(tc.want, escapeWorkflowCommandData(tc.value))
+		})
+	}
+}
+
+func TestEscapeWorkflowCommandProperty(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "no special characters",
+			value: ".github/workflows/ci.yml",
+			want:  ".github/workflows/ci.yml",
+		},
+		{
+			name:  "percent",
+			value: "100%",
+			want:  "100%25",
+		},
+		{
+			name:  "colon",
+			value: "a:b",
+			want:  "a%3Ab",
+		},
+		{
+			name:  "comma",
+			value: "a,b",
+			want:  "a%2Cb",
+		},
+		{
+			name:  "newlines",
+			value: "a\r\nb",
+			want:  "a%0D%0Ab",
+		},
+		{
+			name:  "all of the special characters",
+			value: "%:,\n",
+			want:  "%25%3A%2C%0A",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			a.Equal(tc.want, escapeWorkflowCommandProperty(tc.value))
+		})
+	}
+}
+
+func newTestLintError(filePath string, line, col int, msg string, kind linter.ErrorKind, ruleID string, severity linter.Severity) *linter.Error {
+	return &linter.Error{
+		LintError: actionlint.Error{
+			Message:  msg,
+			Filepath: filePath,
+			Line:     line,
+			Column:   col,
+			Kind:     string(kind),
+		},
+		RepoID:   "owner/repo",
+		RuleID:   ruleID,
+		Severity: severity,
+	}
+}
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	testCases := []struct {
+		name       string
+		lintErrors []*linter.Error
+		want       string
+	}{
+		{
+			name:       "no findings",
+			lintErrors: []*linter.Error{},
+			want:       "",
+		},
+		{
+			name: "findings of severities",
+			lintErrors: []*linter.Error{
+				newTestLintError(".github/workflows/ci.yml", 7, 15, "invalid ref value: action=a/b, expected=SHA, actual=v1", linter.KindUnpinned, linter.RuleIDPinHash, linter.SeverityError),
+				newTestLintError(".github/workflows/ci.yml", 9, 15, "inactive action found", linter.KindInactiveRepo, linter.RuleIDInactiveRepo, linter.SeverityWarning),
+				newTestLintError(".github/workflows/ci.yml", 11, 15, "old release", linter.KindUnexpectedValue, linter.RuleIDReleaseAge, linter.SeverityNotice),
+			},
+			want: dedent.Dedent(`
+				::error file=.github/workflows/ci.yml,line=7,col=15,title=AA001::invalid ref value: action=a/b, expected=SHA, actual=v1
+				::warning file=.github/workflows/ci.yml,line=9,col=15,title=AA005::inactive action found
+				::notice file=.github/workflows/ci.yml,line=11,col=15,title=AA008::old release
+				`),
+		},
+		{
+			name: "special characters",
+			lintErrors: []*linter.Error{
+				newTestLintError("dir:a,b/ci.yml", 1, 2, "100% failed\nsecond line", linter.KindRuntimeError, "", linter.SeverityError),
+			},
+			want: dedent.Dedent(`
+				::error file=dir%3Aa%2Cb/ci.yml,line=1,col=2,title=runtime error::100%25 failed%0Asecond line
+				`),
+		},
+		{
+			name: "no position",
+			lintErrors: []*linter.Error{
+				newTestLintError(".github/workflows/ci.yml", 0, 0, "failed to parse", linter.KindRuntimeError, "", linter.SeverityError),
+			},
+			want: dedent.Dedent(`
+				::error file=.github/workflows/ci.yml,title=runtime error::failed to parse
+				`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			r := require.New(t)
+
+			var b strings.Builder
+			r.NoError(WriteGitHubAnnotations(&b, tc.lintErrors))
+
+			a.Equal(strings.TrimPrefix(tc.want, "\n"), b.String())
+		})
+	}
+}
+
+func TestWriteGitHubStepSummary(t *testing.T) {
+	testCases := []struct {
+		name       string
+		lintErrors []*linter.Error
+		want       string
+	}{
+		{
+			name:       "no findings",
+			lintErrors: []*linter.Error{},
+			want: dedent.Dedent(`
+				## actionarmor
+
+				No findings.
+
+				`),
+		},
+		{
+			name: "findings",
+			lintErrors: []*linter.Error{
+				newTestLintError(".github/workflows/ci.yml", 7, 15, "invalid ref value", linter.KindUnpinned, linter.RuleIDPinHash, linter.SeverityError),
+				newTestLintError(".github/workflows/ci.yml", 9, 15, "inactive action found", linter.KindInactiveRepo, linter.RuleIDInactiveRepo, linter.SeverityWarning),
+				newTestLintError(".github/workflows/ci.yml", 0, 0, "a|b\nc", linter.KindRuntimeError, "", linter.SeverityError),
+			},
+			want: dedent.Dedent(`
+				## actionarmor
+
+				3 findings: 2 errors, 1 warnings, 0 notices
+
+				| Severity | Rule | File | Line | Message |
+				|----------|------|------|-----:|---------|
+				| error | AA001 | owner/repo/.github/workflows/ci.yml | 7 | invalid ref value |
+				| warning | AA005 | owner/repo/.github/workflows/ci.yml | 9 | inactive action found |
+				| error | runtime error | owner/repo/.github/workflows/ci.yml | 0 | a\|b<br>c |
+
+				`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			r := require.New(t)
+
+			var b strings.Builder
+			r.NoError(WriteGitHubStepSummary(&b, tc.lintErrors))
+
+			a.Equal(strings.TrimPrefix(tc.want, "\n"), b.String())
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+)
+
+// JUnit XML report
+// ref: https://github.com/testmoapp/junitxml
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	File      string          `xml:"file,attr,omitempty"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// formatFinding returns a line of a finding: LINE:COL: [SEVERITY RULE: KIND] MESSAGE
+func formatFinding(lerr *linter.Error) string {
+	return fmt.Sprintf("%d:%d: [%s] %s", lerr.LintError.Line, lerr.LintError.Column, lerr.DisplayKind(), lerr.LintError.Message)
+}
+
+// newJUnitTestCase creates a test case of findings. The test case fails if any of the findings is an error.
+// Findings of the other severities are written to the system-out of the test case.
+func newJUnitTestCase(name, className, file string, line int, findings []*linter.Error) junitTestCase {
+	testCase := junitTestCase{
+		Name:      name,
+		ClassName: className,
+		File:      file,
+		Line:      line,
+	}
+
+	errorLines := []string{}
+	otherLines := []string{}
+	var firstError *linter.Error
+
+	for _, lerr := range findings {
+		if lerr.Severity != linter.SeverityError {
+			otherLines = append(otherLines, formatFinding(lerr))
+			continue
+		}
+
+		if firstError == nil {
+			firstError = lerr
+		}
+		errorLines = append(errorLines, formatFinding(lerr))
+	}
+
+	if firstError != nil {
+		testCase.Failure = &junitFailure{
+			Message: firstError.LintError.Message,
+			Type:    firstError.Title(),
+			Text:    strings.Join(errorLines, "\n"),
+		}
+	}
+
+	testCase.SystemOut = strings.Join(otherLines, "\n")
+
+	return testCase
+}
+
+// WriteJUnitReport writes a JUnit XML report of the findings.
+// Each workflow file is a test suite and each 'uses' step is a test case.
+func WriteJUnitReport(w io.Writer, workflows []*WorkflowFindings) error {
+	report := junitTestSuites{
+		Name:       common.ToolName,
+		TestSuites: make([]junitTestSuite, 0, len(workflows)),
+	}
+
+	for _, wf := range workflows {
+		suite := junitTestSuite{
+			Name:      wf.DisplayPath,
+			File:      wf.RelPath,
+			TestCases: make([]junitTestCase, 0, len(wf.Steps)+1),
+		}
+
+		for _, step := range wf.Steps {
+			usage := step.Usage
+
+			var line int
+			if usage.Pos != nil {
+				line = usage.Pos.Line
+			}
+
+			suite.TestCases = append(suite.TestCases, newJUnitTestCase(
+				fmt.Sprintf("%s/%s: %s", usage.JobID, usage.Step(), usage.Uses),
+				wf.DisplayPath, wf.RelPath, line, step.Findings,
+			))
+		}
+
+		if len(wf.Findings) > 0 {
+			suite.TestCases = append(suite.TestCases, newJUnitTestCase(wf.RelPath, wf.DisplayPath, wf.RelPath, 0, wf.Findings))
+		}
+
+		for _, testCase := range suite.TestCases {
+			suite.Tests++
+			if testCase.Failure != nil {
+				suite.Failures++
+			}
+		}
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.TestSuites = append(report.TestSuites, suite)
+	}
+
+	return writeXML(w, report)
+}
+
+// Checkstyle XML report
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// toCheckstyleSeverity converts a severity to a checkstyle severity: error, warning, or info.
+func toCheckstyleSeverity(severity linter.Severity) string {
+	switch severity {
+	case linter.SeverityWarning:
+		return "warning"
+	case linter.SeverityNotice:
+		return "info"
+	default:
+		return "error"
+	}
+}
+
+// WriteCheckstyleReport writes a Checkstyle XML report of the findings.
+// Each workflow file is a file element, including workflow files without findings.
+func WriteCheckstyleReport(w io.Writer, workflows []*WorkflowFindings) error {
+	report := checkstyleReport{
+		Version: "4.3",
+		Files:   make([]checkstyleFile, 0, len(workflows)),
+	}
+
+	for _, wf := range workflows {
+		file := checkstyleFile{
+			Name:   wf.RelPath,
+			Errors: make([]checkstyleError, 0, wf.NumFindings()),
+		}
+
+		findings := append([]*linter.Error{}, wf.Findings...)
+		for _, step := range wf.Steps {
+			findings = append(findings, step.Findings...)
+		}
+
+		for _, lerr := range findings {
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     lerr.LintError.Line,
+				Column:   lerr.LintError.Column,
+				Severity: toCheckstyleSeverity(lerr.Severity),
+				Message:  lerr.LintError.Message,
+				Source:   fmt.Sprintf("%s.%s", common.ToolName, lerr.Title()),
+			})
+		}
+
+		report.Files = append(report.Files, file)
+	}
+
+	return writeXML(w, report)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode XML: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
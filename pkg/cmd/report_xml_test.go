@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/lithammer/dedent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+)
+
+const testReportWorkflowPath = "/path/to/repo/.github/workflows/ci.yml"
+
+var testReportWorkflow = dedent.Dedent(`
+	name: CI
+	on: push
+	jobs:
+	  test:
+	    runs-on: ubuntu-latest
+	    steps:
+	      - uses: actions/checkout@v4
+	      - name: changed files
+	        uses: tj-actions/changed-files@v45
+	`)
+
+// newTestWorkflowFindings groups findings of two workflows: ci.yml has findings of steps and a runtime error,
+// and release.yml has no findings.
+func newTestWorkflowFindings(t *testing.T) []*WorkflowFindings {
+	t.Helper()
+	r := require.New(t)
+
+	params, err := linter.NewWorkflowLintParams()
+	r.NoError(err)
+
+	wfLintInfoList := []linter.WorkflowLintInfo{
+		{
+			FilePath: testReportWorkflowPath,
+			RepoID:   "owner/repo",
+			Content:  []byte(testReportWorkflow),
+			Params:   params,
+		},
+		{
+			FilePath: "/path/to/repo/.github/workflows/release.yml",
+			RepoID:   "owner/repo",
+			Content:  []byte(testReportWorkflow),
+			Params:   params,
+		},
+	}
+
+	newError := func(line int, msg string, kind linter.ErrorKind, ruleID string, severity linter.Severity) *linter.Error {
+		lerr := newTestLintError(testReportWorkflowPath, line, 15, msg, kind, ruleID, severity)
+		lerr.WorkflowAbsFilePath = testReportWorkflowPath
+
+		return lerr
+	}
+
+	lintErrors := []*linter.Error{
+		newError(8, "invalid ref value", linter.KindUnpinned, linter.RuleIDPinHash, linter.SeverityError),
+		newError(10, "inactive action found", linter.KindInactiveRepo, linter.RuleIDInactiveRepo, linter.SeverityWarning),
+		newError(10, "invalid ref value", linter.KindUnpinned, linter.RuleIDPinHash, linter.SeverityNotice),
+		newError(0, "failed to resolve", linter.KindRuntimeError, "", linter.SeverityError),
+	}
+
+	return GroupFindingsByStep(wfLintInfoList, lintErrors, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestGroupFindingsByStep(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	workflows := newTestWorkflowFindings(t)
+	r.Len(workflows, 2)
+
+	ci := workflows[0]
+	a.Equal("owner/repo/"+testReportWorkflowPath, ci.DisplayPath)
+	a.Equal(4, ci.NumFindings())
+	r.Len(ci.Steps, 2)
+	a.Len(ci.Steps[0].Findings, 1)
+	a.Len(ci.Steps[1].Findings, 2)
+
+	// findings that do not match any of the steps are the findings of the workflow
+	r.Len(ci.Findings, 1)
+	a.Equal("failed to resolve", ci.Findings[0].LintError.Message)
+
+	release := workflows[1]
+	a.Equal(0, release.NumFindings())
+	a.Len(release.Steps, 2)
+}
+
+func TestGroupFindingsByStep_UnknownWorkflow(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	lerr := newTestLintError(".github/workflows/other.yml", 3, 1, "failed to parse", linter.KindRuntimeError, "", linter.SeverityError)
+	lerr.WorkflowAbsFilePath = "/path/to/repo/.github/workflows/other.yml"
+
+	workflows := GroupFindingsByStep([]linter.WorkflowLintInfo{}, []*linter.Error{lerr}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	r.Len(workflows, 1)
+
+	a.Equal("owner/repo/.github/workflows/other.yml", workflows[0].DisplayPath)
+	a.Empty(workflows[0].Steps)
+	a.Len(workflows[0].Findings, 1)
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	var b strings.Builder
+	r.NoError(WriteJUnitReport(&b, newTestWorkflowFindings(t)))
+
+	var report junitTestSuites
+	r.NoError(xml.Unmarshal([]byte(b.String()), &report))
+
+	// ci.yml: 2 steps and a workflow-level test case, release.yml: 2 steps
+	a.Equal(5, report.Tests)
+	a.Equal(2, report.Failures)
+	r.Len(report.TestSuites, 2)
+
+	ci := report.TestSuites[0]
+	a.Equal(3, ci.Tests)
+	a.Equal(2, ci.Failures)
+	r.Len(ci.TestCases, 3)
+
+	checkout := ci.TestCases[0]
+	a.Equal("test/#0: actions/checkout@v4", checkout.Name)
+	a.Equal(8, checkout.Line)
+	r.NotNil(checkout.Failure)
+	a.Equal(linter.RuleIDPinHash, checkout.Failure.Type)
+
+	// warnings and notices do not fail a test case
+	changedFiles := ci.TestCases[1]
+	a.Equal("test/changed files: tj-actions/changed-files@v45", changedFiles.Name)
+	a.Nil(changedFiles.Failure)
+	a.Contains(changedFiles.SystemOut, "warning AA005")
+	a.Contains(changedFiles.SystemOut, "notice AA001")
+
+	// findings without a matching step are in the workflow-level test case
+	workflowCase := ci.TestCases[2]
+	a.Equal(testReportWorkflowPath, workflowCase.Name)
+	a.Equal(0, workflowCase.Line)
+	r.NotNil(workflowCase.Failure)
+	a.Equal("failed to resolve", workflowCase.Failure.Message)
+
+	release := report.TestSuites[1]
+	a.Equal(2, release.Tests)
+	a.Equal(0, release.Failures)
+}
+
+func TestWriteCheckstyleReport(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	var b strings.Builder
+	r.NoError(WriteCheckstyleReport(&b, newTestWorkflowFindings(t)))
+
+	var report checkstyleReport
+	r.NoError(xml.Unmarshal([]byte(b.String()), &report))
+
+	// workflows without findings are also reported
+	r.Len(report.Files, 2)
+
+	ci := report.Files[0]
+	a.Equal(testReportWorkflowPath, ci.Name)
+	r.Len(ci.Errors, 4)
+
+	severities := make([]string, 0, len(ci.Errors))
+	sources := make([]string, 0, len(ci.Errors))
+	for _, e := range ci.Errors {
+		severities = append(severities, e.Severity)
+		sources = append(sources, e.Source)
+	}
+	a.Equal([]string{"error", "error", "warning", "info"}, severities)
+	a.Equal([]string{"actionarmor.runtime error", "actionarmor.AA001", "actionarmor.AA005", "actionarmor.AA001"}, sources)
+
+	a.Empty(report.Files[1].Errors)
+}
+
+func TestToCheckstyleSeverity(t *testing.T) {
+	testCases := []struct {
+		severity linter.Severity
+		want     string
+	}{
+		{severity: linter.SeverityError, want: "error"},
+		{severity: linter.SeverityWarning, want: "warning"},
+		{severity: linter.SeverityNotice, want: "info"},
+		{severity: "", want: "error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.severity), func(t *testing.T) {
+			a := assert.New(t)
+
+			a.Equal(tc.want, toCheckstyleSeverity(tc.severity))
+		})
+	}
+}
@@ -2,17 +2,24 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/phsym/console-slog"
 	"github.com/spf13/pflag"
 	"github.com/thombashi/eoe"
 	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"github.com/thombashi/gh-actionarmor/pkg/advisory"
 	"github.com/thombashi/gh-actionarmor/pkg/git"
 	"github.com/thombashi/gh-actionarmor/pkg/linter"
+	"github.com/thombashi/gh-actionarmor/pkg/ratelimit"
 	"github.com/thombashi/gh-actionarmor/pkg/workflow"
 	gitdescribe "github.com/thombashi/gh-git-describe/pkg/executor"
 	"github.com/thombashi/gh-taghash/pkg/resolver"
@@ -60,11 +67,26 @@ func toWorkflowLintOptionsFromFlags(flags LinterFlags, logger *slog.Logger) []li
 		case enforceVerifiedOrganizationFlagName:
 			opts = append(opts, linter.WithEnforceVerifiedOrganization(flags.EnforceVerifiedOrg))
 
+		case requireSignedCommitsFlagName:
+			opts = append(opts, linter.WithRequireSignedCommits(flags.RequireSignedCommits))
+
+		case minReleaseAgeFlagName:
+			opts = append(opts, linter.WithMinReleaseAge(flags.MinReleaseAge))
+
+		case maxRepoInactivityFlagName:
+			opts = append(opts, linter.WithMaxRepoInactivity(flags.MaxRepoInactivity))
+
 		case creatorAllowlistFlagName:
 			opts = append(opts, linter.WithCreatorAllowlist(flags.CreatorAllowlist))
 
 		case actionAllowlistFlagName:
 			opts = append(opts, linter.WithActionAllowlist(flags.ActionAllowlist))
+
+		case deprecatedRuntimesFlagName:
+			opts = append(opts, linter.WithDeprecatedRuntimes(flags.DeprecatedRuntimes))
+
+		case disabledRulesFlagName:
+			opts = append(opts, linter.WithDisabledRules(flags.DisabledRules))
 		}
 	})
 
@@ -113,12 +135,51 @@ func makeLintParams(config *workflow.ActionArmorConfigFile, flags LinterFlags, l
 	return params, nil
 }
 
+func warnUnknownRepoID(logger *slog.Logger, path string) {
+	logger.Warn("could not determine the repository ID. specify the --repo flag to set it explicitly.",
+		slog.String("path", path),
+		slog.String("repo", git.UnknownRepoID),
+	)
+}
+
+func getRepoID(gitExecutor gitexec.GitExecutor, wfInfo *workflow.WorkflowInfo) (string, error) {
+	if wfInfo.RepoID != "" {
+		return wfInfo.RepoID, nil
+	}
+
+	var dirPath string
+	switch {
+	case wfInfo.Project != nil:
+		dirPath = wfInfo.Project.RootDir()
+	case wfInfo.RepoDir != "":
+		dirPath = wfInfo.RepoDir
+	default:
+		warnUnknownRepoID(gitExecutor.GetLogger(), wfInfo.FilePath)
+		return git.UnknownRepoID, nil
+	}
+
+	repoID, err := git.GetRepoIDFromDir(gitExecutor, dirPath)
+	if err != nil {
+		if !errors.Is(err, git.ErrRepoIDNotFound) {
+			return "", err
+		}
+
+		warnUnknownRepoID(gitExecutor.GetLogger(), dirPath)
+
+		return git.UnknownRepoID, nil
+	}
+
+	return repoID, nil
+}
+
 // ToWorkflowLintInfo converts a list of WorkflowInfo to a list of WorkflowLintInfo.
+// If repoID is not empty, it is used as the repository ID of all of the workflows.
 func ToWorkflowLintInfo(
 	wfInfoList []*workflow.WorkflowInfo,
 	config *workflow.ActionArmorConfigFile,
 	gitExecutor gitexec.GitExecutor,
 	flags LinterFlags,
+	repoID string,
 ) ([]linter.WorkflowLintInfo, error) {
 	wfLintInfoList := make([]linter.WorkflowLintInfo, 0, len(wfInfoList))
 
@@ -133,61 +194,182 @@ func ToWorkflowLintInfo(
 			return nil, err
 		}
 
-		repoID, err := git.GetRepoID(gitExecutor, wfInfo.Project)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get the repository ID: %w", err)
+		wfRepoID := repoID
+		if wfRepoID == "" {
+			wfRepoID, err = getRepoID(gitExecutor, wfInfo)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the repository ID: %w", err)
+			}
 		}
 
 		wfLintInfoList = append(wfLintInfoList, linter.WorkflowLintInfo{
-			FilePath: wfInfo.FilePath,
-			Project:  wfInfo.Project,
-			Params:   params,
-			RepoID:   repoID,
+			FilePath:   wfInfo.FilePath,
+			Project:    wfInfo.Project,
+			Params:     params,
+			RepoID:     wfRepoID,
+			Content:    wfInfo.Content,
+			Rev:        wfInfo.Rev,
+			TargetUses: wfInfo.TargetUses,
+			Lockfile:   wfInfo.Lockfile,
 		})
 	}
 
 	return wfLintInfoList, nil
 }
 
+func listRemoteWorkflows(ctx context.Context, flags *Flags, src workflow.Source, logger *slog.Logger) ([]*workflow.WorkflowInfo, error) {
+	targets := make([]*workflow.RemoteTarget, 0, len(flags.Remotes))
+
+	for _, remote := range flags.Remotes {
+		target, err := workflow.ParseRemoteTarget(remote)
+		if err != nil {
+			return nil, err
+		}
+
+		targets = append(targets, target)
+	}
+
+	orgTargets, err := workflow.ListOrgTargets(ctx, src, flags.Orgs, logger)
+	if err != nil {
+		return nil, err
+	}
+	targets = append(targets, orgTargets...)
+
+	return workflow.ListWorkflowsFromSource(ctx, src, targets, logger)
+}
+
+// listWorkflows returns a list of WorkflowInfo from the path arguments and the remote repositories.
+// A path argument "-" reads a workflow from the standard input.
+func listWorkflows(ctx context.Context, args []string, flags *Flags, env *Environment) ([]*workflow.WorkflowInfo, error) {
+	logger := env.Logger
+	paths := make([]string, 0, len(args))
+	wfInfoList := make([]*workflow.WorkflowInfo, 0)
+
+	if len(flags.Remotes) > 0 || len(flags.Orgs) > 0 {
+		l, err := listRemoteWorkflows(ctx, flags, workflow.NewGitHubSource(env.GqlClient), logger)
+		if err != nil {
+			return nil, err
+		}
+
+		wfInfoList = append(wfInfoList, l...)
+	}
+
+	for _, arg := range args {
+		if arg != workflow.StdinPath {
+			paths = append(paths, arg)
+			continue
+		}
+
+		logger.Debug("reading a workflow from the standard input", slog.String("filename", flags.StdinFilename))
+		wfInfo, err := workflow.ReadWorkflow(os.Stdin, flags.StdinFilename, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		wfInfoList = append(wfInfoList, wfInfo)
+	}
+
+	if len(paths) == 0 {
+		return wfInfoList, nil
+	}
+
+	if flags.Recursive {
+		repoPaths, err := workflow.FindRepositories(paths, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Debug("found repositories", slog.Int("count", len(repoPaths)))
+
+		if len(repoPaths) == 0 {
+			return wfInfoList, nil
+		}
+
+		paths = repoPaths
+	}
+
+	var l []*workflow.WorkflowInfo
+	var err error
+	if flags.Rev != "" {
+		l, err = workflow.ListWorkflowsAtRev(env.GitExecutor, paths, flags.Rev, logger)
+	} else {
+		l, err = workflow.ListWorkflows(paths, logger)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	wfInfoList = append(wfInfoList, l...)
+
+	return wfInfoList, nil
+}
+
 type Environment struct {
 	Logger      *slog.Logger
 	EoeParams   *eoe.ExitOnErrorParams
+	Host        string
 	GqlClient   *api.GraphQLClient
 	GitExecutor gitexec.GitExecutor
 	GdExecutor  gitdescribe.Executor
 	Linter      linter.Linter
-}
 
-func NewEnvironment(ctx context.Context, logLevel slog.Level, flags *CacheFlags) (*Environment, error) {
-	logger := newLogger(logLevel)
-	eoeParams := eoe.NewParams().WithLogger(logger).WithContext(ctx)
+	// RateLimiter is a transport of the GitHub clients that tracks the API usage and retries rate limited requests.
+	RateLimiter *ratelimit.Transport
 
-	cacheTTL, err := resolver.ParseCacheTTL(flags.CacheTTLStr)
-	eoe.ExitOnError(err, eoeParams.WithMessage("failed to parse a cache TTL"))
+	// Flags is a set of flags of the execution.
+	Flags *Flags
 
-	if flags.NoCache {
-		cacheTTL.QueryTTL = 0
+	// Workflows is a list of the linted workflows.
+	Workflows []linter.WorkflowLintInfo
+}
+
+// hostCacheDirPath returns a cache directory path for a GitHub host: BASE_DIR/hosts/HOST
+// Caches are separated by hosts since the cached data are keyed by repository IDs (OWNER/NAME) without a host.
+func hostCacheDirPath(baseDirPath, host string) (string, error) {
+	if baseDirPath == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get the user cache directory: %w", err)
+		}
+
+		baseDirPath = filepath.Join(userCacheDir, "gh-"+common.ToolName)
 	}
 
+	return filepath.Join(baseDirPath, "hosts", host), nil
+}
+
+func newHostClients(
+	host string,
+	cacheDirPath string,
+	cacheTTL *resolver.CacheTTL,
+	noCache bool,
+	rateLimiter *ratelimit.Transport,
+	logger *slog.Logger,
+) (*linter.HostClients, error) {
 	gqlClient, err := api.NewGraphQLClient(api.ClientOptions{
-		CacheTTL: cacheTTL.QueryTTL,
+		Host:      host,
+		CacheTTL:  cacheTTL.QueryTTL,
+		Transport: rateLimiter,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a GitHub client: %w", err)
 	}
 
-	gitExecutor, err := gitexec.New(&gitexec.Params{
-		Logger: logger,
+	// tags locked by lockfiles are resolved without cached responses to detect moved tags
+	uncachedGqlClient, err := api.NewGraphQLClient(api.ClientOptions{
+		Host:      host,
+		Transport: rateLimiter,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create a git executer: %w", err)
+		return nil, fmt.Errorf("failed to create a GitHub client: %w", err)
 	}
 
-	gdExecutor, err := gitdescribe.New(&gitdescribe.Params{
-		Logger:         logger,
-		LogWithPackage: true,
-		CacheDirPath:   flags.CacheDirPath,
-		CacheTTL:       cacheTTL.GitFileTTL,
+	// clone repositories from the host regardless of the GH_HOST environment variable
+	gdExecutor, err := git.NewDescribeExecutor(&git.DescribeExecutorParams{
+		Host:         host,
+		Logger:       logger,
+		CacheDirPath: cacheDirPath,
+		CacheTTL:     cacheTTL.GitFileTTL,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a git-describe executor: %w", err)
@@ -198,63 +380,205 @@ func NewEnvironment(ctx context.Context, logLevel slog.Level, flags *CacheFlags)
 		GitDescExecutor: gdExecutor,
 		Logger:          logger,
 		LogWithPackage:  true,
-		CacheDirPath:    flags.CacheDirPath,
-		ClearCache:      flags.NoCache,
+		CacheDirPath:    cacheDirPath,
+		ClearCache:      noCache,
 		CacheTTL:        *cacheTTL,
 	})
-	eoe.ExitOnError(err, eoeParams.WithMessage("failed to create a resolver"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a resolver: %w", err)
+	}
+
+	return &linter.HostClients{
+		GqlClient:         gqlClient,
+		UncachedGqlClient: uncachedGqlClient,
+		GdExecutor:        gdExecutor,
+		Resolver:          r,
+	}, nil
+}
+
+func NewEnvironment(ctx context.Context, logLevel slog.Level, hostname string, flags *CacheFlags, advisories *advisory.Database, offline bool) (*Environment, error) {
+	logger := newLogger(logLevel)
+	eoeParams := eoe.NewParams().WithLogger(logger).WithContext(ctx)
+
+	cacheTTL, err := resolver.ParseCacheTTL(flags.CacheTTLStr)
+	eoe.ExitOnError(err, eoeParams.WithMessage("failed to parse a cache TTL"))
+
+	if flags.NoCache {
+		cacheTTL.QueryTTL = 0
+	}
+
+	if hostname == "" {
+		hostname, _ = auth.DefaultHost()
+	}
+
+	logger.Debug("GitHub host", slog.String("host", hostname))
+
+	gitExecutor, err := gitexec.New(&gitexec.Params{
+		Logger: logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a git executer: %w", err)
+	}
 
-	linter := linter.NewLinter(logger, gqlClient, gdExecutor, r)
+	// a rate limiter is shared by the clients of all of the hosts to report the API usage of the run
+	rateLimiter := ratelimit.New(&ratelimit.Params{
+		Logger: logger,
+	})
+
+	// clients are not created in the offline mode since they require an authentication token
+	clients := &linter.HostClients{}
+	if !offline {
+		cacheDirPath, err := hostCacheDirPath(flags.CacheDirPath, hostname)
+		if err != nil {
+			return nil, err
+		}
+
+		clients, err = newHostClients(hostname, cacheDirPath, cacheTTL, flags.NoCache, rateLimiter, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	linter, err := linter.New(&linter.Params{
+		Logger:            logger,
+		Host:              hostname,
+		GqlClient:         clients.GqlClient,
+		UncachedGqlClient: clients.UncachedGqlClient,
+		GdExecutor:        clients.GdExecutor,
+		Resolver:          clients.Resolver,
+		NewHostClients: func(host string) (*linter.HostClients, error) {
+			cacheDirPath, err := hostCacheDirPath(flags.CacheDirPath, host)
+			if err != nil {
+				return nil, err
+			}
+
+			logger.Debug("creating clients for a host", slog.String("host", host), slog.String("cache-dir", cacheDirPath))
+
+			return newHostClients(host, cacheDirPath, cacheTTL, flags.NoCache, rateLimiter, logger)
+		},
+		Advisories: advisories,
+		Offline:    offline,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a linter: %w", err)
+	}
 
 	return &Environment{
 		Logger:      logger,
 		EoeParams:   eoeParams,
-		GqlClient:   gqlClient,
+		Host:        hostname,
+		GqlClient:   clients.GqlClient,
 		GitExecutor: gitExecutor,
-		GdExecutor:  gdExecutor,
+		GdExecutor:  clients.GdExecutor,
 		Linter:      linter,
+		RateLimiter: rateLimiter,
 	}, nil
 }
 
-func Execute() (*Environment, []*linter.Error) {
-	var err error
-	var config *workflow.ActionArmorConfigFile
+// LogAPIUsage logs the aggregate GitHub API usage of the run per host.
+// Responses served from the cache are not counted.
+func (env *Environment) LogAPIUsage() {
+	if env.RateLimiter == nil {
+		return
+	}
 
-	flags, args, err := NewFlags(common.ToolName, []NewFlagSetFunc{
-		NewRunFlagSet,
-		NewCacheFlagSet,
-		NewLinterFlagSet,
-	})
-	eoe.ExitOnError(err, eoe.NewParams().WithMessage("failed to set flags"))
+	for _, usage := range env.RateLimiter.Usages() {
+		attrs := []any{
+			slog.String("host", usage.Host),
+			slog.Int("requests", usage.Requests),
+			slog.Int("retries", usage.Retries),
+			slog.Int("rate-limited", usage.RateLimited),
+		}
+		if usage.Remaining >= 0 {
+			attrs = append(attrs, slog.String("remaining", fmt.Sprintf("%d/%d", usage.Remaining, usage.Limit)))
+		}
+		if usage.ResetAt != nil {
+			attrs = append(attrs, slog.Time("reset", *usage.ResetAt))
+		}
+
+		if usage.RateLimited > 0 {
+			env.Logger.Warn("GitHub API usage", attrs...)
+			continue
+		}
+
+		env.Logger.Info("GitHub API usage", attrs...)
+	}
+}
+
+// prepare creates an environment and lists the workflows to process.
+func prepare(ctx context.Context, flags *Flags, args []string) *Environment {
+	var config *workflow.ActionArmorConfigFile
 
 	var logLevel slog.Level
-	err = logLevel.UnmarshalText([]byte(flags.LogLevelStr))
+	err := logLevel.UnmarshalText([]byte(flags.LogLevelStr))
 	eoe.ExitOnError(err, eoe.NewParams().WithMessage("failed to get a slog level"))
 
-	ctx := context.Background()
+	var advisories *advisory.Database
+	if len(flags.AdvisoryDBPaths) > 0 {
+		advisories, err = advisory.Load(flags.AdvisoryDBPaths...)
+		eoe.ExitOnError(err, eoe.NewParams().WithMessage("failed to load the advisory database"))
+	}
 
-	env, err := NewEnvironment(ctx, logLevel, &flags.CacheFlags)
-	eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to create an environment"))
+	env, err := NewEnvironment(ctx, logLevel, flags.Hostname, &flags.CacheFlags, advisories, flags.Offline)
+	eoe.ExitOnError(err, eoe.NewParams().WithMessage("failed to create an environment"))
 
-	wfInfoList, err := workflow.ListWorkflows(args, env.Logger)
+	if advisories != nil {
+		env.Logger.Debug("loaded the advisory database", slog.Int("advisories", advisories.Len()))
+	}
+
+	env.Flags = flags
+
+	wfInfoList, err := listWorkflows(ctx, args, flags, env)
 	eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to list workflow file paths"))
 
-	// 再帰的に ListWorkflows を行う関数
+	if flags.DiffBase != "" {
+		err = workflow.SetDiffBase(env.GitExecutor, wfInfoList, flags.DiffBase, env.Logger)
+		eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to compute differences from the base revision"))
+	}
 
 	if flags.ConfigFilePath != "" {
 		config = workflow.NewConfigFileFromFile(flags.ConfigFilePath)
 	}
 
-	wfLintInfoList, err := ToWorkflowLintInfo(wfInfoList, config, env.GitExecutor, flags.LinterFlags)
+	wfLintInfoList, err := ToWorkflowLintInfo(wfInfoList, config, env.GitExecutor, flags.LinterFlags, flags.RepoID)
 	eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to convert workflow info"))
 
+	for _, wfLintInfo := range wfLintInfoList {
+		err = env.Linter.ValidateParams(wfLintInfo.Params)
+		eoe.ExitOnError(err, env.EoeParams.WithMessage(fmt.Sprintf("invalid lint parameters: path=%s", wfLintInfo.FilePath)))
+	}
+
+	env.Workflows = wfLintInfoList
+
+	return env
+}
+
+func Execute() (*Environment, []*linter.Error) {
+	flags, args, err := NewFlags(common.ToolName, []NewFlagSetFunc{
+		NewRunFlagSet,
+		NewCacheFlagSet,
+		NewLinterFlagSet,
+		NewOutputFlagSet,
+	})
+	eoe.ExitOnError(err, eoe.NewParams().WithMessage("failed to set flags"))
+
+	if !slices.Contains(outputFormats, flags.OutputFormat) {
+		eoe.ExitOnError(
+			fmt.Errorf("expected=%s, actual=%s", strings.Join(outputFormats, "|"), flags.OutputFormat),
+			eoe.NewParams().WithMessage("invalid --format flag value"),
+		)
+	}
+
+	ctx := context.Background()
+	env := prepare(ctx, flags, args)
+
 	globalLintParams := linter.GlobalLintParams{
 		NumWorkers: flags.NumWorkers,
 	}
 
 	env.Logger.Debug("linter process parameters", slog.String("global", globalLintParams.String()))
 
-	lintErrors, err := env.Linter.LintWorkflowFilesContext(ctx, globalLintParams, wfLintInfoList)
+	lintErrors, err := env.Linter.LintWorkflowFilesContext(ctx, globalLintParams, env.Workflows)
 	eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to lint"))
 
 	return env, lintErrors
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/thombashi/eoe"
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+	"github.com/thombashi/gh-actionarmor/pkg/sbom"
+	"github.com/thombashi/gh-actionarmor/pkg/workflow"
+)
+
+// output formats of the sbom subcommand
+const (
+	sbomFormatCycloneDX = "cyclonedx"
+	sbomFormatSPDX      = "spdx"
+)
+
+var sbomFormats = []string{sbomFormatCycloneDX, sbomFormatSPDX}
+
+// usesSource represents where 'uses' values are read from.
+type usesSource struct {
+	repoID string
+	rev    string
+
+	// fsys is a file system of the repository root. It is nil when the files of the repository are not available.
+	fsys fs.FS
+
+	params *linter.WorkflowLintParams
+}
+
+func (s usesSource) displayPath(p string) string {
+	return linter.FormatDisplayPath(s.repoID, s.rev, p)
+}
+
+type actionEdge struct {
+	from   *sbom.Component
+	action linter.Action
+}
+
+// sbomBuilder builds an SBOM document from workflows.
+// Actions are added to the document after resolving their commit hashes and tags.
+type sbomBuilder struct {
+	doc         *sbom.Document
+	defaultHost string
+	edges       []actionEdge
+	logger      *slog.Logger
+}
+
+func (b *sbomBuilder) workflowComponent(src usesSource, filePath string) *sbom.Component {
+	return b.doc.AddComponent(&sbom.Component{
+		BomRef: "workflow:" + src.displayPath(filePath),
+		Type:   sbom.ComponentTypeWorkflow,
+		Name:   src.displayPath(filePath),
+	})
+}
+
+func (b *sbomBuilder) containerComponent(image string) *sbom.Component {
+	ref := sbom.ParseDockerImage(image)
+	name := ref.Repository
+	if ref.Registry != "" {
+		name = ref.Registry + "/" + ref.Repository
+	}
+
+	purl := sbom.DockerPurl(image)
+
+	return b.doc.AddComponent(&sbom.Component{
+		BomRef:  purl,
+		Type:    sbom.ComponentTypeContainer,
+		Name:    name,
+		Version: ref.Version(),
+		Purl:    purl,
+	})
+}
+
+func isWorkflowFilePath(p string) bool {
+	ext := path.Ext(p)
+
+	return path.Dir(p) == ".github/workflows" && (ext == ".yaml" || ext == ".yml")
+}
+
+// addUsage adds a dependency from a component to the target of a 'uses' value.
+// Steps of local composite actions are added recursively when the files of the repository are available.
+func (b *sbomBuilder) addUsage(from *sbom.Component, usage *linter.ActionUsage, src usesSource) {
+	switch usage.Kind {
+	case linter.UsesKindAction:
+		b.edges = append(b.edges, actionEdge{from: from, action: usage.Action})
+
+	case linter.UsesKindDocker:
+		b.doc.AddDependency(from, b.containerComponent(usage.DockerImage()))
+
+	case linter.UsesKindLocal:
+		localPath := path.Clean(strings.TrimPrefix(usage.Uses, "./"))
+
+		if isWorkflowFilePath(localPath) {
+			b.doc.AddDependency(from, b.workflowComponent(src, localPath))
+			return
+		}
+
+		bomRef := "local:" + src.displayPath(localPath)
+		_, visited := b.doc.Component(bomRef)
+
+		to := b.doc.AddComponent(&sbom.Component{
+			BomRef:  bomRef,
+			Type:    sbom.ComponentTypeAction,
+			Name:    src.displayPath(localPath),
+			SubPath: localPath,
+		})
+		b.doc.AddDependency(from, to)
+
+		if visited || src.fsys == nil {
+			return
+		}
+
+		metadata, err := workflow.ReadActionMetadata(src.fsys, localPath)
+		if err != nil {
+			b.logger.Debug("skip reading a local action", slog.String("path", localPath), slog.Any("error", err))
+			return
+		}
+
+		if image, found := strings.CutPrefix(metadata.Runs.Image, "docker://"); found {
+			b.doc.AddDependency(to, b.containerComponent(image))
+		}
+
+		if !metadata.IsComposite() {
+			return
+		}
+
+		for _, step := range metadata.Runs.Steps {
+			if step.Uses == "" {
+				continue
+			}
+
+			stepUsage, err := linter.ParseUses(step.Uses, src.params)
+			if err != nil {
+				b.logger.Warn("invalid uses value in a local action", slog.String("path", localPath), slog.Any("error", err))
+				continue
+			}
+
+			b.addUsage(to, stepUsage, src)
+		}
+	}
+}
+
+func (b *sbomBuilder) addWorkflow(wfLintInfo linter.WorkflowLintInfo) error {
+	content, err := readWorkflowContent(wfLintInfo)
+	if err != nil {
+		return err
+	}
+
+	usages, err := linter.ListUsages(wfLintInfo, content)
+	if err != nil {
+		return err
+	}
+
+	relPath, err := wfLintInfo.RelPath()
+	if err != nil {
+		return err
+	}
+
+	src := usesSource{
+		repoID: wfLintInfo.RepoID,
+		rev:    wfLintInfo.Rev,
+		params: wfLintInfo.Params,
+	}
+	if wfLintInfo.Project != nil && wfLintInfo.Rev == "" {
+		src.fsys = os.DirFS(wfLintInfo.Project.RootDir())
+	}
+
+	wfComponent := b.workflowComponent(src, relPath)
+	b.doc.AddRoot(wfComponent)
+
+	for _, usage := range usages {
+		b.addUsage(wfComponent, usage, src)
+	}
+
+	return nil
+}
+
+// addActions adds the actions to the document with the details of the actions.
+func (b *sbomBuilder) addActions(details map[string]*linter.ActionDetail) {
+	for _, edge := range b.edges {
+		action := edge.action
+
+		host := action.Host
+		if host == "" {
+			host = b.defaultHost
+		}
+
+		purl := sbom.ActionPurl(host, action.Owner, action.Name, action.SubPath(), action.Ref)
+		component := &sbom.Component{
+			BomRef:  purl,
+			Type:    sbom.ComponentTypeAction,
+			Name:    action.ID,
+			Version: action.Ref,
+			Purl:    purl,
+			VCSURL:  sbom.VCSURL(host, action.Owner, action.Name),
+			SubPath: action.SubPath(),
+		}
+
+		if detail, exist := details[actionDetailKey(action)]; exist {
+			component.CommitHash = detail.CommitHash
+			component.Tags = detail.Tags
+		} else if action.IsPinnedBySHA() {
+			component.CommitHash = action.Ref
+		}
+
+		b.doc.AddDependency(edge.from, b.doc.AddComponent(component))
+	}
+}
+
+func sbomDocumentName(wfLintInfoList []linter.WorkflowLintInfo) string {
+	repoIDs := make([]string, 0, len(wfLintInfoList))
+	for _, wfLintInfo := range wfLintInfoList {
+		repoIDs = append(repoIDs, wfLintInfo.RepoID)
+	}
+
+	slices.Sort(repoIDs)
+	repoIDs = slices.Compact(repoIDs)
+
+	if len(repoIDs) == 1 {
+		return repoIDs[0]
+	}
+
+	return fmt.Sprintf("gh-%s-workflows", common.ToolName)
+}
+
+// BuildSBOM builds an SBOM document of the actions and the container images used in the workflows.
+func BuildSBOM(ctx context.Context, env *Environment, wfLintInfoList []linter.WorkflowLintInfo, numWorkers int64) *sbom.Document {
+	b := &sbomBuilder{
+		doc:         sbom.NewDocument(sbomDocumentName(wfLintInfoList)),
+		defaultHost: env.Host,
+		edges:       make([]actionEdge, 0),
+		logger:      env.Logger,
+	}
+
+	for _, wfLintInfo := range wfLintInfoList {
+		if err := b.addWorkflow(wfLintInfo); err != nil {
+			env.Logger.Error("failed to list actions", slog.String("path", wfLintInfo.FilePath), slog.Any("error", err))
+		}
+	}
+
+	actions := make([]linter.Action, 0, len(b.edges))
+	for _, edge := range b.edges {
+		actions = append(actions, edge.action)
+	}
+
+	b.addActions(describeActions(ctx, env.Linter, actions, numWorkers, env.Logger))
+
+	return b.doc
+}
+
+// ExecuteSbom executes the sbom subcommand.
+func ExecuteSbom(subcommand *Subcommand) {
+	flags, args, err := NewSubcommandFlags(common.ToolName, subcommand, []NewFlagSetFunc{
+		NewRunFlagSet,
+		NewCacheFlagSet,
+		NewSbomFlagSet,
+	})
+	eoe.ExitOnError(err, eoe.NewParams().WithMessage("failed to set flags"))
+
+	if !slices.Contains(sbomFormats, flags.SbomFormat) {
+		eoe.ExitOnError(
+			fmt.Errorf("expected=%s, actual=%s", strings.Join(sbomFormats, "|"), flags.SbomFormat),
+			eoe.NewParams().WithMessage("invalid --format flag value"),
+		)
+	}
+
+	ctx := context.Background()
+	env := prepare(ctx, flags, args)
+	defer env.LogAPIUsage()
+
+	doc := BuildSBOM(ctx, env, env.Workflows, flags.NumWorkers)
+
+	switch flags.SbomFormat {
+	case sbomFormatSPDX:
+		err = doc.WriteSPDX(os.Stdout)
+	default:
+		err = doc.WriteCycloneDX(os.Stdout)
+	}
+	eoe.ExitOnError(err, env.EoeParams.WithMessage("failed to write the SBOM"))
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+)
+
+// Subcommand represents a subcommand of the tool.
+type Subcommand struct {
+	// Name is a name of the subcommand. It can consist of multiple words (e.g. 'lock update').
+	Name string
+
+	// Description is a short description of the subcommand.
+	Description string
+
+	run func(*Subcommand)
+}
+
+// Execute executes the subcommand.
+func (s *Subcommand) Execute() {
+	s.run(s)
+}
+
+var subcommands = []*Subcommand{
+	{
+		Name:        "list",
+		Description: "list actions used in workflows with their locations",
+		run:         ExecuteList,
+	},
+	{
+		Name:        "sbom",
+		Description: "output an SBOM of actions and container images used in workflows (CycloneDX or SPDX JSON)",
+		run:         ExecuteSbom,
+	},
+	{
+		Name:        "lock update",
+		Description: "resolve tag references of actions and record their commit hashes to the lockfile",
+		run:         ExecuteLockUpdate,
+	},
+}
+
+// numArgs returns the number of command line arguments that the subcommand name consists of.
+func (s *Subcommand) numArgs() int {
+	return len(strings.Fields(s.Name))
+}
+
+// endOfFlags is an argument that terminates flags and subcommands. The following arguments are paths.
+const endOfFlags = "--"
+
+// LookupSubcommand returns a subcommand that matches the leading command line arguments. It returns nil if not found.
+// Paths that have the same name as a subcommand (e.g. a directory named 'list') can be linted by
+// preceding them with "--": e.g. gh actionarmor -- list
+func LookupSubcommand(args []string) *Subcommand {
+	if len(args) > 0 && args[0] == endOfFlags {
+		return nil
+	}
+
+	for _, subcommand := range subcommands {
+		fields := strings.Fields(subcommand.Name)
+		if len(args) < len(fields) {
+			continue
+		}
+
+		if slices.Equal(fields, args[:len(fields)]) {
+			return subcommand
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupSubcommand(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "no arguments",
+			args: []string{},
+			want: "",
+		},
+		{
+			name: "subcommand",
+			args: []string{"list", "--group-by-action", "."},
+			want: "list",
+		},
+		{
+			name: "subcommand of multiple words",
+			args: []string{"lock", "update"},
+			want: "lock update",
+		},
+		{
+			name: "incomplete subcommand",
+			args: []string{"lock"},
+			want: "",
+		},
+		{
+			name: "path",
+			args: []string{".github/workflows/ci.yml"},
+			want: "",
+		},
+		{
+			name: "path with the same name as a subcommand",
+			args: []string{"--", "list"},
+			want: "",
+		},
+		{
+			name: "flag before a subcommand name",
+			args: []string{"--log-level", "debug", "sbom"},
+			want: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			subcommand := LookupSubcommand(tc.args)
+			if tc.want == "" {
+				a.Nil(subcommand)
+				return
+			}
+
+			if a.NotNil(subcommand) {
+				a.Equal(tc.want, subcommand.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/thombashi/gh-actionarmor/pkg/linter"
+)
+
+// RepositorySummary represents a summary of the linting results of a repository.
+type RepositorySummary struct {
+	// RepoID is a repository ID (OWNER/NAME).
+	RepoID string
+
+	// Path is a path to the repository.
+	Path string
+
+	// NumWorkflows is the number of linted workflow files.
+	NumWorkflows int
+
+	// NumFindings is the number of findings.
+	NumFindings int
+}
+
+func workflowRepoPath(wfLintInfo linter.WorkflowLintInfo) string {
+	if wfLintInfo.Project != nil {
+		return wfLintInfo.Project.RootDir()
+	}
+
+	return ""
+}
+
+// SummarizeByRepository summarizes the linting results per repository.
+func SummarizeByRepository(wfLintInfoList []linter.WorkflowLintInfo, lintErrors []*linter.Error) []*RepositorySummary {
+	summaryMap := map[string]*RepositorySummary{}
+
+	for _, wfLintInfo := range wfLintInfoList {
+		path := workflowRepoPath(wfLintInfo)
+		key := wfLintInfo.RepoID + "\x00" + path
+
+		summary, exist := summaryMap[key]
+		if !exist {
+			summary = &RepositorySummary{
+				RepoID: wfLintInfo.RepoID,
+				Path:   path,
+			}
+			summaryMap[key] = summary
+		}
+
+		summary.NumWorkflows++
+	}
+
+	for _, lerr := range lintErrors {
+		var path string
+		if lerr.Project != nil {
+			path = lerr.Project.RootDir()
+		}
+
+		if summary, exist := summaryMap[lerr.RepoID+"\x00"+path]; exist {
+			summary.NumFindings++
+		}
+	}
+
+	summaries := make([]*RepositorySummary, 0, len(summaryMap))
+	for _, summary := range summaryMap {
+		summaries = append(summaries, summary)
+	}
+
+	slices.SortFunc(summaries, func(a, b *RepositorySummary) int {
+		if c := strings.Compare(a.RepoID, b.RepoID); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return summaries
+}
+
+// WriteRepositorySummary writes a summary table of the repositories.
+func WriteRepositorySummary(w io.Writer, summaries []*RepositorySummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "REPOSITORY\tWORKFLOWS\tFINDINGS\tPATH")
+
+	var totalWorkflows, totalFindings int
+	for _, s := range summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", s.RepoID, s.NumWorkflows, s.NumFindings, s.Path)
+
+		totalWorkflows += s.NumWorkflows
+		totalFindings += s.NumFindings
+	}
+
+	fmt.Fprintf(tw, "TOTAL (%d repositories)\t%d\t%d\t\n", len(summaries), totalWorkflows, totalFindings)
+
+	return tw.Flush()
+}
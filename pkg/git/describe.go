@@ -0,0 +1,283 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2"
+	"github.com/cli/go-gh/v2/pkg/repository"
+	gitdescribe "github.com/thombashi/gh-git-describe/pkg/executor"
+	"github.com/thombashi/go-gitexec"
+	"golang.org/x/sync/singleflight"
+)
+
+const describeCacheDirName = "gh-git-describe"
+
+// DescribeExecutorParams is a set of parameters for NewDescribeExecutor.
+type DescribeExecutorParams struct {
+	// Host is a GitHub host to clone repositories from.
+	Host string
+
+	// GitExecutor is a git executor. A new executor is created if nil.
+	GitExecutor gitexec.GitExecutor
+
+	// Logger is a logger.
+	Logger *slog.Logger
+
+	// CacheDirPath is a base directory path of cloned repositories. If it is empty, a user cache directory is used.
+	// The directory should be separated per host since cloned repositories are stored without the host.
+	CacheDirPath string
+
+	// CacheTTL is the retention duration of cloned repositories.
+	CacheTTL time.Duration
+}
+
+// DescribeExecutor is an adapter of the git-describe executor of gh-git-describe that clones repositories
+// from a specific GitHub host.
+//
+// gh-git-describe clones repositories by 'gh repo clone OWNER/NAME' that follows the GH_HOST environment variable
+// of the process. DescribeExecutor clones repositories by 'gh repo clone HOST/OWNER/NAME' to the cache directory of
+// gh-git-describe in advance, and the git commands are executed by gh-git-describe with the cloned repositories.
+// The adapter is no longer required once gh-git-describe clones repositories from a given host.
+type DescribeExecutor struct {
+	gitdescribe.Executor
+
+	host         string
+	logger       *slog.Logger
+	cacheDirPath string
+	cacheTTL     time.Duration
+
+	// mu guards cloned repositories from being replaced while git commands are running on them
+	mu    sync.RWMutex
+	group singleflight.Group
+}
+
+var _ gitdescribe.Executor = (*DescribeExecutor)(nil)
+
+// NewDescribeExecutor creates a new DescribeExecutor instance.
+func NewDescribeExecutor(params *DescribeExecutorParams) (*DescribeExecutor, error) {
+	if params.Host == "" {
+		return nil, fmt.Errorf("require a host")
+	}
+
+	logger := params.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	cacheDirPath := params.CacheDirPath
+	if cacheDirPath == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the user cache directory: %w", err)
+		}
+
+		cacheDirPath = userCacheDir
+	}
+
+	executor, err := gitdescribe.New(&gitdescribe.Params{
+		GitExecutor:  params.GitExecutor,
+		Logger:       logger,
+		CacheDirPath: cacheDirPath,
+		CacheTTL:     params.CacheTTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a git-describe executor: %w", err)
+	}
+
+	return &DescribeExecutor{
+		Executor:     executor,
+		host:         params.Host,
+		logger:       logger,
+		cacheDirPath: filepath.Join(cacheDirPath, describeCacheDirName),
+		cacheTTL:     params.CacheTTL,
+	}, nil
+}
+
+// parseRepository parses a repository ID (OWNER/NAME or HOST/OWNER/NAME) of the host of the executor.
+func (e *DescribeExecutor) parseRepository(repoID string) (repository.Repository, error) {
+	if repoID == "" {
+		return repository.Repository{}, fmt.Errorf("require a repository ID")
+	}
+
+	repo, err := repository.Parse(repoID)
+	if err != nil {
+		return repository.Repository{}, fmt.Errorf("failed to parse the repository ID: %w", err)
+	}
+
+	// repository.Parse fills the default host of gh for a repository ID without a host
+	if strings.Count(repoID, "/") < 2 {
+		repo.Host = e.host
+	}
+
+	if repo.Host != e.host {
+		return repository.Repository{}, fmt.Errorf("repository of another host: repo=%s, host=%s", repoID, e.host)
+	}
+
+	return repo, nil
+}
+
+// RunRepoClone clones the specified GitHub repository.
+func (e *DescribeExecutor) RunRepoClone(params *gitdescribe.RepoCloneParams) (string, error) {
+	return e.RunRepoCloneContext(context.Background(), params)
+}
+
+// RunRepoCloneContext clones the specified GitHub repository as a bare repository and returns the directory path:
+// CACHE_DIR/gh-git-describe/OWNER/NAME. A cloned repository is reused until the cache TTL expires.
+// Concurrent clones of a repository are coalesced into a clone that is not canceled by the cancellation of ctx,
+// since the clone is shared by the other callers.
+func (e *DescribeExecutor) RunRepoCloneContext(ctx context.Context, params *gitdescribe.RepoCloneParams) (string, error) {
+	repo, err := e.parseRepository(params.RepoID)
+	if err != nil {
+		return "", err
+	}
+
+	return e.cloneRepository(ctx, params, repo)
+}
+
+func (e *DescribeExecutor) cloneRepository(ctx context.Context, params *gitdescribe.RepoCloneParams, repo repository.Repository) (string, error) {
+	outputDir := filepath.Join(e.cacheDirPath, repo.Owner, repo.Name)
+	cacheTTL := e.cacheTTL
+	if params.CacheTTL > 0 {
+		cacheTTL = params.CacheTTL
+	}
+
+	sharedCtx := context.WithoutCancel(ctx)
+	resultCh := e.group.DoChan(outputDir, func() (interface{}, error) {
+		info, err := os.Stat(outputDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to get the information of the directory: %w", err)
+		}
+		if err == nil && time.Since(info.ModTime()) < cacheTTL {
+			e.logger.Debug("repo cache found", slog.String("path", outputDir))
+			return nil, nil
+		}
+
+		return nil, e.clone(sharedCtx, repo, outputDir)
+	})
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case result := <-resultCh:
+		if result.Err != nil {
+			return "", result.Err
+		}
+	}
+
+	return outputDir, nil
+}
+
+func (e *DescribeExecutor) clone(ctx context.Context, repo repository.Repository, outputDir string) error {
+	fullName := fmt.Sprintf("%s/%s/%s", repo.Host, repo.Owner, repo.Name)
+	e.logger.Debug("cloning a repository", slog.String("repo", fullName), slog.String("path", outputDir))
+
+	if err := os.MkdirAll(filepath.Dir(outputDir), 0750); err != nil {
+		return fmt.Errorf("failed to create the parent directory: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp(filepath.Dir(outputDir), ".clone-")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if _, stderr, err := gh.ExecContext(ctx, "repo", "clone", fullName, tempDir, "--", "--bare"); err != nil {
+		return fmt.Errorf("failed to clone the repository: repo=%s, error=%w, stderr=%s", fullName, err, stderr.String())
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := os.RemoveAll(outputDir); err != nil {
+		return fmt.Errorf("failed to remove the directory: %w", err)
+	}
+	if err := os.Rename(tempDir, outputDir); err != nil {
+		return fmt.Errorf("failed to rename the directory: %w", err)
+	}
+
+	return nil
+}
+
+// delegate clones the repository from the host of the executor and runs a git command of gh-git-describe
+// on the cloned repository.
+func (e *DescribeExecutor) delegate(
+	ctx context.Context,
+	params *gitdescribe.RepoCloneParams,
+	run func(params *gitdescribe.RepoCloneParams) (string, error),
+) (string, error) {
+	repo, err := e.parseRepository(params.RepoID)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := e.cloneRepository(ctx, params, repo); err != nil {
+		return "", fmt.Errorf("failed to clone the repository: %w", err)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	// gh-git-describe finds the cloned repository by OWNER/NAME.
+	// the clone never expires in gh-git-describe, since the expiration is handled by the executor.
+	return run(&gitdescribe.RepoCloneParams{
+		RepoID:   fmt.Sprintf("%s/%s", repo.Owner, repo.Name),
+		CacheTTL: math.MaxInt64,
+	})
+}
+
+// RunGit runs the specified git command.
+func (e *DescribeExecutor) RunGit(params *gitdescribe.RepoCloneParams, command string, args ...string) (string, error) {
+	return e.RunGitContext(context.Background(), params, command, args...)
+}
+
+// RunGitContext runs the specified git command in the cloned repository with the specified context.
+func (e *DescribeExecutor) RunGitContext(ctx context.Context, params *gitdescribe.RepoCloneParams, command string, args ...string) (string, error) {
+	return e.delegate(ctx, params, func(params *gitdescribe.RepoCloneParams) (string, error) {
+		return e.Executor.RunGitContext(ctx, params, command, args...)
+	})
+}
+
+// RunGitDescribe runs the 'git describe' command for the specified GitHub repository.
+func (e *DescribeExecutor) RunGitDescribe(params *gitdescribe.RepoCloneParams, args ...string) (string, error) {
+	return e.RunGitDescribeContext(context.Background(), params, args...)
+}
+
+// RunGitDescribeContext runs the 'git describe' command for the specified GitHub repository with the specified context.
+func (e *DescribeExecutor) RunGitDescribeContext(ctx context.Context, params *gitdescribe.RepoCloneParams, args ...string) (string, error) {
+	return e.delegate(ctx, params, func(params *gitdescribe.RepoCloneParams) (string, error) {
+		return e.Executor.RunGitDescribeContext(ctx, params, args...)
+	})
+}
+
+// RunGitRevParse runs the 'git rev-parse' command for the specified GitHub repository.
+func (e *DescribeExecutor) RunGitRevParse(params *gitdescribe.RepoCloneParams, args ...string) (string, error) {
+	return e.RunGitRevParseContext(context.Background(), params, args...)
+}
+
+// RunGitRevParseContext runs the 'git rev-parse' command for the specified GitHub repository with the specified context.
+func (e *DescribeExecutor) RunGitRevParseContext(ctx context.Context, params *gitdescribe.RepoCloneParams, args ...string) (string, error) {
+	return e.delegate(ctx, params, func(params *gitdescribe.RepoCloneParams) (string, error) {
+		return e.Executor.RunGitRevParseContext(ctx, params, args...)
+	})
+}
+
+// RunGitRevList runs the 'git rev-list' command for the specified GitHub repository.
+func (e *DescribeExecutor) RunGitRevList(params *gitdescribe.RepoCloneParams, args ...string) (string, error) {
+	return e.RunGitRevListContext(context.Background(), params, args...)
+}
+
+// RunGitRevListContext runs the 'git rev-list' command for the specified GitHub repository with the specified context.
+func (e *DescribeExecutor) RunGitRevListContext(ctx context.Context, params *gitdescribe.RepoCloneParams, args ...string) (string, error) {
+	return e.delegate(ctx, params, func(params *gitdescribe.RepoCloneParams) (string, error) {
+		return e.Executor.RunGitRevListContext(ctx, params, args...)
+	})
+}
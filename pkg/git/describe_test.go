@@ -0,0 +1,84 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gitdescribe "github.com/thombashi/gh-git-describe/pkg/executor"
+	"github.com/thombashi/go-gitexec"
+)
+
+func TestDescribeExecutor(t *testing.T) {
+	r := require.New(t)
+
+	gitExecutor, err := gitexec.New(&gitexec.Params{})
+	r.NoError(err)
+
+	cacheDir := t.TempDir()
+	executor, err := NewDescribeExecutor(&DescribeExecutorParams{
+		Host:         "github.example.com",
+		GitExecutor:  gitExecutor,
+		CacheDirPath: cacheDir,
+		CacheTTL:     time.Hour,
+	})
+	r.NoError(err)
+
+	// prepare a cached clone to avoid cloning the repository from the host
+	clonedDir := filepath.Join(cacheDir, describeCacheDirName, "octocat", "hello-world")
+	r.NoError(os.MkdirAll(clonedDir, 0755))
+	result, err := gitExecutor.RunGit("init", "--quiet", "--bare", clonedDir)
+	r.NoError(err, result.Stderr.String())
+
+	testCases := []struct {
+		name    string
+		repoID  string
+		wantDir string
+		wantErr bool
+	}{
+		{
+			name:    "repository ID without a host",
+			repoID:  "octocat/hello-world",
+			wantDir: clonedDir,
+		},
+		{
+			name:    "repository ID with the host",
+			repoID:  "github.example.com/octocat/hello-world",
+			wantDir: clonedDir,
+		},
+		{
+			name:    "repository ID of another host",
+			repoID:  "github.com/octocat/hello-world",
+			wantErr: true,
+		},
+		{
+			name:    "empty repository ID",
+			repoID:  "",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			r := require.New(t)
+
+			params := &gitdescribe.RepoCloneParams{RepoID: tc.repoID}
+
+			dir, err := executor.RunRepoClone(params)
+			if tc.wantErr {
+				a.Error(err)
+				return
+			}
+			r.NoError(err)
+			a.Equal(tc.wantDir, dir)
+
+			stdout, err := executor.RunGit(params, "rev-parse", "--is-bare-repository")
+			r.NoError(err)
+			a.Equal("true", stdout)
+		})
+	}
+}
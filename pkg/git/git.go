@@ -2,7 +2,10 @@ package git
 
 import (
 	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/cli/go-gh/v2/pkg/repository"
 	"github.com/rhysd/actionlint"
@@ -11,31 +14,149 @@ import (
 	"github.com/thombashi/go-gitexec"
 )
 
-var remoteCache = make(map[string]string)
+// UnknownRepoID is a placeholder repository ID used when the repository ID could not be determined.
+const UnknownRepoID = "unknown"
+
+const defaultRemoteName = "origin"
+
+// envGitHubRepository is an environment variable that holds the repository ID (OWNER/NAME) on GitHub Actions runners.
+const envGitHubRepository = "GITHUB_REPOSITORY"
+
+var ErrRepoIDNotFound = fmt.Errorf("repository ID not found")
+
+// remoteCache is a cache of repository IDs keyed by directory paths.
+var remoteCache = struct {
+	mu      sync.Mutex
+	repoIDs map[string]string
+}{
+	repoIDs: make(map[string]string),
+}
+
+// ParseRepoID parses a repository ID string (OWNER/NAME or HOST/OWNER/NAME) and returns the normalized OWNER/NAME.
+func ParseRepoID(s string) (string, error) {
+	repo, err := repository.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse a repository ID: %w", err)
+	}
+
+	return resolver.ToRepoID(repo), nil
+}
+
+func getRemoteURL(executor gitexec.GitExecutor, dirPath, remoteName string) (string, error) {
+	result, err := executor.RunGit("-C", dirPath, "config", "--get", fmt.Sprintf("remote.%s.url", remoteName))
+	if result == nil {
+		return "", fmt.Errorf("failed to get the remote URL: remote=%s, error=%w", remoteName, err)
+	}
+
+	switch result.ExitCode {
+	case 0:
+		return strings.TrimSpace(result.Stdout.String()), nil
+	case 1:
+		// exit code 1 means the key was not found
+		return "", nil
+	}
+
+	return "", fmt.Errorf("failed to get the remote URL: remote=%s, exit-code=%d, stderr=%s",
+		remoteName, result.ExitCode, strings.TrimSpace(result.Stderr.String()))
+}
+
+func listRemotes(executor gitexec.GitExecutor, dirPath string) ([]string, error) {
+	result, err := executor.RunGit("-C", dirPath, "remote")
+	if result == nil {
+		return nil, fmt.Errorf("failed to list remotes: %w", err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list remotes: %s", result.Stderr.String())
+	}
+
+	remotes := strings.Fields(result.Stdout.String())
+	slices.Sort(remotes)
+
+	return remotes, nil
+}
+
+func findRemoteURL(executor gitexec.GitExecutor, dirPath string) (string, error) {
+	remoteURL, err := getRemoteURL(executor, dirPath, defaultRemoteName)
+	if err != nil {
+		return "", err
+	}
+	if remoteURL != "" {
+		return remoteURL, nil
+	}
+
+	// fall back to other remotes when the 'origin' remote does not exist:
+	// e.g. a worktree with a differently named remote
+	remotes, err := listRemotes(executor, dirPath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, remote := range remotes {
+		if remote == defaultRemoteName {
+			continue
+		}
+
+		remoteURL, err := getRemoteURL(executor, dirPath, remote)
+		if err != nil {
+			return "", err
+		}
+		if remoteURL != "" {
+			if logger := executor.GetLogger(); logger != nil {
+				logger.Debug(fmt.Sprintf("use the '%s' remote instead of '%s'", remote, defaultRemoteName))
+			}
+			return remoteURL, nil
+		}
+	}
+
+	return "", nil
+}
 
 // GetRepoID returns the repository ID (OWNER/NAME) of a Git repository from a actionlint.Project.
 func GetRepoID(executor gitexec.GitExecutor, proj *actionlint.Project) (string, error) {
-	if repoID, exist := remoteCache[proj.RootDir()]; exist {
+	return GetRepoIDFromDir(executor, proj.RootDir())
+}
+
+// GetRepoIDFromDir returns the repository ID (OWNER/NAME) of a Git repository that contains the directory.
+//
+// The repository ID is determined in the following order:
+//  1. the URL of the 'origin' remote
+//  2. the URL of another remote
+//  3. the GITHUB_REPOSITORY environment variable
+//
+// ErrRepoIDNotFound is returned when none of them are available.
+func GetRepoIDFromDir(executor gitexec.GitExecutor, dirPath string) (string, error) {
+	remoteCache.mu.Lock()
+	repoID, exist := remoteCache.repoIDs[dirPath]
+	remoteCache.mu.Unlock()
+	if exist {
 		return repoID, nil
 	}
 
-	result, err := executor.RunGit("-C", proj.RootDir(), "config", "--get", "remote.origin.url")
+	remoteURL, err := findRemoteURL(executor, dirPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to get the remote origin URL: %w", err)
+		return "", err
 	}
 
-	if result.ExitCode != 0 {
-		return "", fmt.Errorf("failed to get the remote origin URL: %s", result.Stderr.String())
-	}
+	if remoteURL != "" {
+		repo, err := repository.Parse(remoteURL)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse the remote URL: %w", err)
+		}
 
-	remoteURL := strings.TrimSpace(result.Stdout.String())
-	repo, err := repository.Parse(remoteURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse the remote origin URL: %w", err)
+		repoID = resolver.ToRepoID(repo)
+	} else if v := os.Getenv(envGitHubRepository); v != "" {
+		repoID, err = ParseRepoID(v)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s environment variable: %w", envGitHubRepository, err)
+		}
+	} else {
+		return "", fmt.Errorf("%w: path=%s", ErrRepoIDNotFound, dirPath)
 	}
 
-	repoID := resolver.ToRepoID(repo)
-	remoteCache[proj.RootDir()] = repoID
+	remoteCache.mu.Lock()
+	remoteCache.repoIDs[dirPath] = repoID
+	remoteCache.mu.Unlock()
 
 	return repoID, nil
 }
@@ -0,0 +1,120 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thombashi/go-gitexec"
+)
+
+func TestGetRepoIDFromDir(t *testing.T) {
+	r := require.New(t)
+
+	executor, err := gitexec.New(&gitexec.Params{})
+	r.NoError(err)
+
+	initRepo := func(t *testing.T, remotes map[string]string) string {
+		t.Helper()
+
+		dir := t.TempDir()
+		_, err := executor.RunGit("-C", dir, "init", "--quiet")
+		r.NoError(err)
+
+		for name, url := range remotes {
+			_, err := executor.RunGit("-C", dir, "remote", "add", name, url)
+			r.NoError(err)
+		}
+
+		return dir
+	}
+
+	testCases := []struct {
+		name       string
+		remotes    map[string]string
+		envRepo    string
+		want       string
+		wantErrIs  error
+		wantAnyErr bool
+	}{
+		{
+			name: "origin remote",
+			remotes: map[string]string{
+				"origin":   "https://github.com/owner/repo.git",
+				"upstream": "https://github.com/upstream/repo.git",
+			},
+			envRepo: "env-owner/env-repo",
+			want:    "owner/repo",
+		},
+		{
+			name: "fallback to another remote",
+			remotes: map[string]string{
+				"upstream": "git@github.com:upstream/repo.git",
+			},
+			envRepo: "env-owner/env-repo",
+			want:    "upstream/repo",
+		},
+		{
+			name:    "fallback to GITHUB_REPOSITORY",
+			remotes: map[string]string{},
+			envRepo: "env-owner/env-repo",
+			want:    "env-owner/env-repo",
+		},
+		{
+			name:      "not found",
+			remotes:   map[string]string{},
+			envRepo:   "",
+			wantErrIs: ErrRepoIDNotFound,
+		},
+		{
+			name:       "invalid GITHUB_REPOSITORY",
+			remotes:    map[string]string{},
+			envRepo:    "invalid",
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envGitHubRepository, tc.envRepo)
+			dir := initRepo(t, tc.remotes)
+
+			got, err := GetRepoIDFromDir(executor, dir)
+			if tc.wantErrIs != nil {
+				require.ErrorIs(t, err, tc.wantErrIs)
+				return
+			}
+			if tc.wantAnyErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestGetRemoteURL(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	executor, err := gitexec.New(&gitexec.Params{})
+	r.NoError(err)
+
+	dir := t.TempDir()
+	_, err = executor.RunGit("-C", dir, "init", "--quiet")
+	r.NoError(err)
+
+	// a remote that does not exist is not an error
+	remoteURL, err := getRemoteURL(executor, dir, defaultRemoteName)
+	r.NoError(err)
+	a.Empty(remoteURL)
+
+	// other failures of git are errors: e.g. an invalid config file
+	r.NoError(os.WriteFile(filepath.Join(dir, ".git", "config"), []byte("[invalid"), 0644))
+	_, err = getRemoteURL(executor, dir, defaultRemoteName)
+	a.Error(err)
+}
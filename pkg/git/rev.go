@@ -0,0 +1,147 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/thombashi/go-gitexec"
+)
+
+// ResolveRev returns the commit hash of a revision in a Git repository that contains the directory.
+func ResolveRev(executor gitexec.GitExecutor, dirPath, rev string) (string, error) {
+	result, err := executor.RunGit("-C", dirPath, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if result == nil {
+		return "", fmt.Errorf("failed to resolve a revision: rev=%s, error=%w", rev, err)
+	}
+
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("unknown revision: rev=%s, path=%s", rev, dirPath)
+	}
+
+	return strings.TrimSpace(result.Stdout.String()), nil
+}
+
+// RevFS is a read-only file system that reads files from Git objects at a revision
+// without checking out the revision.
+type RevFS struct {
+	executor gitexec.GitExecutor
+	dirPath  string
+	rev      string
+}
+
+var _ fs.ReadFileFS = (*RevFS)(nil)
+
+// NewRevFS creates a new RevFS instance.
+// dirPath is a path to a directory in a Git repository (either a working tree or a bare repository).
+func NewRevFS(executor gitexec.GitExecutor, dirPath, rev string) *RevFS {
+	return &RevFS{
+		executor: executor,
+		dirPath:  dirPath,
+		rev:      rev,
+	}
+}
+
+// Rev returns the revision of the file system.
+func (f RevFS) Rev() string {
+	return f.rev
+}
+
+// DirPath returns the directory path of the Git repository.
+func (f RevFS) DirPath() string {
+	return f.dirPath
+}
+
+// ReadFile reads a file at the revision. name is a slash-separated path from the repository root.
+func (f RevFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+
+	result, err := f.executor.RunGit("-C", f.dirPath, "cat-file", "blob", fmt.Sprintf("%s:%s", f.rev, name))
+	if result == nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+
+	if result.ExitCode != 0 {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return result.Stdout.Bytes(), nil
+}
+
+// Open opens a file at the revision. Directories are not supported.
+func (f RevFS) Open(name string) (fs.File, error) {
+	data, err := f.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revFile{
+		Reader: bytes.NewReader(data),
+		info: revFileInfo{
+			name: path.Base(name),
+			size: int64(len(data)),
+		},
+	}, nil
+}
+
+// ListFiles returns slash-separated paths of the files in a directory at the revision.
+// dir is a slash-separated path from the repository root. Subdirectories are not included.
+func (f RevFS) ListFiles(dir string) ([]string, error) {
+	// the directory is resolved from the repository root even if the directory path of the file system is a subdirectory
+	result, err := f.executor.RunGit("-C", f.dirPath, "ls-tree", "--full-tree", f.rev, "--", strings.TrimSuffix(dir, "/")+"/")
+	if result == nil {
+		return nil, fmt.Errorf("failed to list files: rev=%s, dir=%s, error=%w", f.rev, dir, err)
+	}
+
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list files: rev=%s, dir=%s, stderr=%s", f.rev, dir, result.Stderr.String())
+	}
+
+	paths := make([]string, 0)
+	for _, line := range strings.Split(result.Stdout.String(), "\n") {
+		// format: <mode> SP <type> SP <object> TAB <file>
+		meta, filePath, found := strings.Cut(line, "\t")
+		if !found {
+			continue
+		}
+
+		fields := strings.Fields(meta)
+		if len(fields) < 2 || fields[1] != "blob" {
+			continue
+		}
+
+		paths = append(paths, filePath)
+	}
+
+	return paths, nil
+}
+
+type revFile struct {
+	*bytes.Reader
+	info revFileInfo
+}
+
+func (f *revFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *revFile) Close() error {
+	return nil
+}
+
+type revFileInfo struct {
+	name string
+	size int64
+}
+
+func (i revFileInfo) Name() string       { return i.name }
+func (i revFileInfo) Size() int64        { return i.size }
+func (i revFileInfo) Mode() fs.FileMode  { return 0444 }
+func (i revFileInfo) ModTime() time.Time { return time.Time{} }
+func (i revFileInfo) IsDir() bool        { return false }
+func (i revFileInfo) Sys() any           { return nil }
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thombashi/go-gitexec"
+)
+
+func TestRevFS(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	executor, err := gitexec.New(&gitexec.Params{})
+	r.NoError(err)
+
+	dir := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+
+		args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		result, err := executor.RunGit(args...)
+		r.NoError(err, result.Stderr.String())
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		r.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		r.NoError(os.WriteFile(p, []byte(content), 0644))
+	}
+
+	runGit("init", "--quiet")
+	writeFile(".github/workflows/ci.yaml", "v1")
+	writeFile(".github/workflows/sub/ignored.yaml", "sub")
+	runGit("add", "-A")
+	runGit("commit", "--quiet", "-m", "first")
+
+	writeFile(".github/workflows/ci.yaml", "v2")
+	writeFile(".github/workflows/release.yml", "release")
+	runGit("add", "-A")
+	runGit("commit", "--quiet", "-m", "second")
+
+	_, err = ResolveRev(executor, dir, "unknown-rev")
+	r.Error(err)
+
+	commitHash, err := ResolveRev(executor, dir, "HEAD~1")
+	r.NoError(err)
+	a.Len(commitHash, 40)
+
+	revFS := NewRevFS(executor, dir, "HEAD~1")
+
+	files, err := revFS.ListFiles(".github/workflows")
+	r.NoError(err)
+	a.Equal([]string{".github/workflows/ci.yaml"}, files)
+
+	data, err := revFS.ReadFile(".github/workflows/ci.yaml")
+	r.NoError(err)
+	a.Equal("v1", string(data))
+
+	data, err = fs.ReadFile(revFS, ".github/workflows/ci.yaml")
+	r.NoError(err)
+	a.Equal("v1", string(data))
+
+	_, err = revFS.ReadFile(".github/workflows/release.yml")
+	r.ErrorIs(err, fs.ErrNotExist)
+
+	files, err = NewRevFS(executor, dir, "HEAD").ListFiles(".github/workflows/")
+	r.NoError(err)
+	a.Equal([]string{".github/workflows/ci.yaml", ".github/workflows/release.yml"}, files)
+
+	// paths are resolved from the repository root in a subdirectory of the repository
+	subRevFS := NewRevFS(executor, filepath.Join(dir, ".github"), "HEAD")
+
+	files, err = subRevFS.ListFiles(".github/workflows")
+	r.NoError(err)
+	a.Equal([]string{".github/workflows/ci.yaml", ".github/workflows/release.yml"}, files)
+
+	data, err = subRevFS.ReadFile(".github/workflows/ci.yaml")
+	r.NoError(err)
+	a.Equal("v2", string(data))
+}
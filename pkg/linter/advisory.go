@@ -0,0 +1,75 @@
+package linter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/thombashi/gh-actionarmor/pkg/advisory"
+)
+
+// advisoryRefs returns the ref of an action and the tags that point to the same commit.
+// Tags are resolved on a best-effort basis: the ref itself is matched against advisories if the resolution failed.
+func (l *linter) advisoryRefs(ctx context.Context, action Action) []string {
+	refs := []string{action.Ref}
+
+	sha := action.Ref
+	if !action.IsPinnedBySHA() {
+		gitTag, err := l.resolveGitTag(ctx, action.Repository(), action.Ref)
+		if err != nil {
+			l.logger.Debug("failed to resolve a git tag", slog.String("action", action.String()), slog.Any("error", err))
+			return refs
+		}
+
+		sha = gitTag.CommitHash
+		refs = append(refs, sha)
+	}
+
+	tagNames, err := l.resolveGitTagNamesFromSha(ctx, action.Repository(), sha)
+	if err != nil {
+		l.logger.Debug("failed to resolve git tags", slog.String("action", action.String()), slog.Any("error", err))
+		return refs
+	}
+
+	return append(refs, tagNames...)
+}
+
+func formatAdvisory(a *advisory.Advisory) string {
+	if a.Severity == "" {
+		return a.ID
+	}
+
+	return fmt.Sprintf("%s(%s)", a.ID, a.Severity)
+}
+
+// checkAdvisories checks if an action refers to a version or a commit affected by the advisories of the local database.
+// The ref of the action is matched first without network access.
+// The tags of the same commit are resolved only if the ref did not match and the linter is not offline.
+func (l *linter) checkAdvisories(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) *Error {
+	if l.advisories == nil || l.advisories.Len() == 0 {
+		return nil
+	}
+
+	names := []string{action.ID, action.RepoID()}
+	matched := l.advisories.Match(names, []string{action.Ref})
+	if len(matched) == 0 && !l.offline {
+		matched = l.advisories.Match(names, l.advisoryRefs(ctx, action))
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(matched))
+	for _, a := range matched {
+		ids = append(ids, formatAdvisory(a))
+	}
+
+	msg := fmt.Sprintf("vulnerable action found: action=%s, ref=%s, advisories=[%s]",
+		action.ID, shortenHash(action.Ref), strings.Join(ids, ", "))
+	if summary := strings.TrimSpace(matched[0].Summary); summary != "" {
+		msg += fmt.Sprintf(", summary=%q", summary)
+	}
+
+	return NewError(msg, wfLintInfo, action.Pos, KindVulnerableAction)
+}
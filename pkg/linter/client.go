@@ -0,0 +1,73 @@
+package linter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/thombashi/gh-git-describe/pkg/executor"
+	"github.com/thombashi/gh-taghash/pkg/resolver"
+)
+
+// HostClients is a set of clients to access a GitHub host.
+type HostClients struct {
+	// GqlClient is a GitHub GraphQL client for the host.
+	GqlClient *api.GraphQLClient
+
+	// UncachedGqlClient is a GitHub GraphQL client for the host that does not cache responses.
+	// GqlClient is used if it is nil.
+	UncachedGqlClient *api.GraphQLClient
+
+	// GdExecutor is a git-describe executor for the host.
+	GdExecutor executor.Executor
+
+	// Resolver is a git tag resolver for the host.
+	Resolver *resolver.Resolver
+}
+
+// NewHostClientsFunc creates a new HostClients instance for a GitHub host.
+type NewHostClientsFunc func(host string) (*HostClients, error)
+
+type hostClientPool struct {
+	mu             sync.Mutex
+	defaultHost    string
+	defaultClients *HostClients
+	clients        map[string]*HostClients
+	newHostClients NewHostClientsFunc
+}
+
+func newHostClientPool(defaultHost string, defaultClients *HostClients, newHostClients NewHostClientsFunc) *hostClientPool {
+	return &hostClientPool{
+		defaultHost:    defaultHost,
+		defaultClients: defaultClients,
+		clients:        map[string]*HostClients{},
+		newHostClients: newHostClients,
+	}
+}
+
+// get returns the clients for the host.
+// If the host is empty or the same as the default host, the default clients are returned.
+func (p *hostClientPool) get(host string) (*HostClients, error) {
+	if host == "" || host == p.defaultHost {
+		return p.defaultClients, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if clients, exist := p.clients[host]; exist {
+		return clients, nil
+	}
+
+	if p.newHostClients == nil {
+		return nil, fmt.Errorf("clients for the host are not available: host=%s", host)
+	}
+
+	clients, err := p.newHostClients(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clients: host=%s, error=%w", host, err)
+	}
+	p.clients[host] = clients
+
+	return clients, nil
+}
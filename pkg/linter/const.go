@@ -4,10 +4,19 @@ package linter
 type ErrorKind string
 
 const (
-	KindArchivedActionUsed ErrorKind = "archived action action is being used"
-	KindRuntimeError       ErrorKind = "runtime error"
-	KindUnexpectedValue    ErrorKind = "unexpected value"
-	KindUnpinned           ErrorKind = "must be pinned by hash"
+	KindArchivedActionUsed     ErrorKind = "archived action action is being used"
+	KindRuntimeError           ErrorKind = "runtime error"
+	KindUnexpectedValue        ErrorKind = "unexpected value"
+	KindUnpinned               ErrorKind = "must be pinned by hash"
+	KindNotAllowlistedHash     ErrorKind = "SHA is not allowlisted"
+	KindUnverifiedOrganization ErrorKind = "owner must be a verified organization"
+	KindTagMoved               ErrorKind = "tag moved from the locked commit"
+	KindUnsignedCommit         ErrorKind = "pinned commit must be signed"
+	KindUnexpectedSigner       ErrorKind = "pinned commit must be signed by an expected signer"
+	KindTooNewRelease          ErrorKind = "release is younger than the minimum release age"
+	KindDeprecatedRuntime      ErrorKind = "action runs on a deprecated runtime"
+	KindInactiveRepo           ErrorKind = "repository must be actively maintained"
+	KindVulnerableAction       ErrorKind = "action is affected by a security advisory"
 )
 
 var OfficialCreators = []string{
@@ -0,0 +1,103 @@
+package linter
+
+import (
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// RepoActivity represents the latest activities of an action repository.
+type RepoActivity struct {
+	// IsArchived is true if the repository is archived.
+	IsArchived bool
+
+	// PushedAt is the date of the latest push to the repository.
+	PushedAt *time.Time
+
+	// LatestReleasePublishedAt is the date of the latest release. It is nil if the repository has no release.
+	LatestReleasePublishedAt *time.Time
+}
+
+// LastActiveAt returns the date of the latest activity: the later of the latest push and the latest release.
+// It returns nil if the repository has neither.
+func (r RepoActivity) LastActiveAt() *time.Time {
+	lastActiveAt := r.PushedAt
+
+	if r.LatestReleasePublishedAt != nil && (lastActiveAt == nil || r.LatestReleasePublishedAt.After(*lastActiveAt)) {
+		lastActiveAt = r.LatestReleasePublishedAt
+	}
+
+	return lastActiveAt
+}
+
+// IsInactive returns true if the repository has had no activity for longer than maxInactivity.
+func (r RepoActivity) IsInactive(now time.Time, maxInactivity time.Duration) bool {
+	lastActiveAt := r.LastActiveAt()
+	if lastActiveAt == nil {
+		return true
+	}
+
+	return now.Sub(*lastActiveAt) > maxInactivity
+}
+
+func (l *linter) getRepoActivity(a Action) (*RepoActivity, error) {
+	return l.lookups.activities.Do(repoKey(a.Host, a.Owner, a.Name), func() (*RepoActivity, error) {
+		return l.fetchRepoActivity(a)
+	})
+}
+
+func (l *linter) fetchRepoActivity(a Action) (*RepoActivity, error) {
+	var queryRepoActivity struct {
+		Repository struct {
+			IsArchived    bool
+			PushedAt      *time.Time
+			LatestRelease *struct {
+				PublishedAt *time.Time
+			}
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner": githubv4.String(a.Owner),
+		"name":  githubv4.String(a.Name),
+	}
+	queryParams, err := l.getQueryParams(a.Host, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query(&queryRepoActivity, queryParams); err != nil {
+		return nil, err
+	}
+
+	activity := &RepoActivity{
+		IsArchived: queryRepoActivity.Repository.IsArchived,
+		PushedAt:   queryRepoActivity.Repository.PushedAt,
+	}
+	if queryRepoActivity.Repository.LatestRelease != nil {
+		activity.LatestReleasePublishedAt = queryRepoActivity.Repository.LatestRelease.PublishedAt
+	}
+
+	return activity, nil
+}
+
+// isInactiveAction checks if the repository of an action has had no commits or releases for longer than
+// the maximum inactivity. It also returns the date of the latest activity, which is nil if unknown.
+func (l *linter) isInactiveAction(a Action, params *WorkflowLintParams) (bool, *time.Time, error) {
+	if params.MaxRepoInactivity == nil || *params.MaxRepoInactivity <= 0 {
+		return false, nil, nil
+	}
+
+	activity, err := l.getRepoActivity(a)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// archived repositories are reported by the archived action rule
+	if activity.IsArchived {
+		return false, nil, nil
+	}
+
+	maxInactivity := time.Duration(*params.MaxRepoInactivity) * 24 * time.Hour
+
+	return activity.IsInactive(time.Now(), maxInactivity), activity.LastActiveAt(), nil
+}
@@ -0,0 +1,134 @@
+package linter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoActivity_IsInactive(t *testing.T) {
+	a := assert.New(t)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -10)
+	old := now.AddDate(-2, 0, 0)
+	maxInactivity := 365 * 24 * time.Hour
+
+	testCases := []struct {
+		name             string
+		activity         RepoActivity
+		wantLastActiveAt *time.Time
+		wantInactive     bool
+	}{
+		{
+			name:             "recently pushed",
+			activity:         RepoActivity{PushedAt: &recent},
+			wantLastActiveAt: &recent,
+			wantInactive:     false,
+		},
+		{
+			name:             "recently released",
+			activity:         RepoActivity{PushedAt: &old, LatestReleasePublishedAt: &recent},
+			wantLastActiveAt: &recent,
+			wantInactive:     false,
+		},
+		{
+			name:             "inactive",
+			activity:         RepoActivity{PushedAt: &old, LatestReleasePublishedAt: &old},
+			wantLastActiveAt: &old,
+			wantInactive:     true,
+		},
+		{
+			name:             "no activity",
+			activity:         RepoActivity{},
+			wantLastActiveAt: nil,
+			wantInactive:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		a.Equal(tc.wantLastActiveAt, tc.activity.LastActiveAt(), tc.name)
+		a.Equal(tc.wantInactive, tc.activity.IsInactive(now, maxInactivity), tc.name)
+	}
+}
+
+func TestInactiveRepoRule(t *testing.T) {
+	old := time.Now().AddDate(-2, 0, 0)
+	recent := time.Now().AddDate(0, 0, -10)
+
+	testCases := []struct {
+		name          string
+		activity      *RepoActivity
+		maxInactivity int
+		wantKinds     []string
+	}{
+		{
+			name:          "inactive",
+			activity:      &RepoActivity{PushedAt: &old},
+			maxInactivity: 365,
+			wantKinds:     []string{string(KindInactiveRepo)},
+		},
+		{
+			name:          "active",
+			activity:      &RepoActivity{PushedAt: &recent},
+			maxInactivity: 365,
+			wantKinds:     nil,
+		},
+		{
+			// archived repositories are reported by the archived action rule
+			name:          "archived",
+			activity:      &RepoActivity{IsArchived: true, PushedAt: &old},
+			maxInactivity: 365,
+			wantKinds:     nil,
+		},
+		{
+			name:          "disabled",
+			activity:      &RepoActivity{PushedAt: &old},
+			maxInactivity: 0,
+			wantKinds:     nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			r := require.New(t)
+
+			l := &linter{lookups: newLookups()}
+			action := Action{Host: "github.com", Owner: "octocat", Name: "inactive-action", ID: "octocat/inactive-action"}
+			l.lookups.activities.Store(repoKey(action.Host, action.Owner, action.Name), tc.activity)
+
+			params, err := NewWorkflowLintParams(WithMaxRepoInactivity(tc.maxInactivity))
+			r.NoError(err)
+
+			rule := inactiveRepoRule{builtinRule{l, RuleIDInactiveRepo, "", SeverityWarning, true}}
+			lintErrors := rule.Check(context.Background(), action, WorkflowLintInfo{Params: params, RepoID: "owner/repo"})
+
+			kinds := make([]string, 0, len(lintErrors))
+			for _, lerr := range lintErrors {
+				kinds = append(kinds, lerr.LintError.Kind)
+			}
+			if tc.wantKinds == nil {
+				a.Empty(kinds)
+				return
+			}
+			a.Equal(tc.wantKinds, kinds)
+		})
+	}
+}
+
+func TestBuiltinRules_InactiveRepoSeverity(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	registry, err := NewRuleRegistry(newBuiltinRules(&linter{})...)
+	r.NoError(err)
+
+	// inactive repositories are warning-level findings
+	rule, exist := registry.Lookup(RuleIDInactiveRepo)
+	r.True(exist)
+	a.Equal(SeverityWarning, rule.DefaultSeverity())
+}
@@ -1,13 +1,16 @@
 package linter
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"runtime"
 	"slices"
 	"strings"
 	"sync"
@@ -18,6 +21,7 @@ import (
 	"github.com/cli/go-gh/v2/pkg/repository"
 	"github.com/rhysd/actionlint"
 	"github.com/shurcooL/githubv4"
+	"github.com/thombashi/gh-actionarmor/pkg/advisory"
 	"github.com/thombashi/gh-actionarmor/pkg/workflow"
 	"github.com/thombashi/gh-git-describe/pkg/executor"
 	"github.com/thombashi/gh-taghash/pkg/resolver"
@@ -28,6 +32,9 @@ import (
 
 const maxCommentLen = 20
 
+// ErrOffline is returned by lookups that require network access when the linter is in the offline mode.
+var ErrOffline = errors.New("network access is disabled in the offline mode")
+
 const (
 	DefaultExcludeOfficialActions   = true
 	DefaultExcludeVerifiedCreators  = false
@@ -35,6 +42,9 @@ const (
 	DefaultAllowArchivedRepo        = true
 	DefaultEnforcePinHash           = true
 	DefaultEnforceVerifiedOrg       = false
+	DefaultRequireSignedCommits     = false
+	DefaultMinReleaseAge            = 0
+	DefaultMaxRepoInactivity        = 0
 )
 
 var reNewLines = regexp.MustCompile(`[\r\n\s]+`)
@@ -96,6 +106,56 @@ type Error struct {
 	LintError           actionlint.Error
 	WorkflowAbsFilePath string
 	Project             *actionlint.Project
+
+	// RepoID is a repository ID (OWNER/NAME) of the project that contains the workflow file.
+	RepoID string
+
+	// Source is the content of the workflow file.
+	// It is nil when the workflow was read from the WorkflowAbsFilePath.
+	Source []byte
+
+	// Rev is a Git revision that the workflow was read from.
+	Rev string
+
+	// RuleID is an ID of the rule that reported the finding: e.g. AA001.
+	// It is empty for findings that are not reported by rules (e.g. runtime errors).
+	RuleID string
+
+	// Severity is a severity of the finding.
+	Severity Severity
+}
+
+// Title returns a title of the finding: the rule ID if exists, otherwise the kind of the finding.
+func (e Error) Title() string {
+	if e.RuleID != "" {
+		return e.RuleID
+	}
+
+	return e.LintError.Kind
+}
+
+// DisplayKind returns a kind of the finding with the severity and the rule ID: SEVERITY RULE_ID: KIND
+func (e Error) DisplayKind() string {
+	if e.RuleID == "" {
+		return fmt.Sprintf("%s: %s", e.Severity, e.LintError.Kind)
+	}
+
+	return fmt.Sprintf("%s %s: %s", e.Severity, e.RuleID, e.LintError.Kind)
+}
+
+// FormatDisplayPath returns a path of a file with the repository ID: OWNER/NAME/PATH,
+// or OWNER/NAME@REV:PATH if the file is read from a revision of the repository.
+func FormatDisplayPath(repoID, rev, filePath string) string {
+	if rev != "" {
+		return fmt.Sprintf("%s@%s:%s", repoID, rev, filePath)
+	}
+
+	return fmt.Sprintf("%s/%s", repoID, filePath)
+}
+
+// DisplayPath returns a path of the workflow file with the repository ID: OWNER/NAME/PATH or OWNER/NAME@REV:PATH.
+func (e Error) DisplayPath() string {
+	return FormatDisplayPath(e.RepoID, e.Rev, e.LintError.Filepath)
 }
 
 // QueryParams is a set of parameters for a query.
@@ -118,6 +178,16 @@ func query[T any](query *T, params *QueryParams) error {
 	return nil
 }
 
+// queryRaw executes a query string that cannot be expressed by a struct: e.g. a batched query of aliased nodes.
+// The response is decoded to resp.
+func queryRaw(ctx context.Context, query string, resp any, params *QueryParams) error {
+	if err := params.Client.DoWithContext(ctx, query, params.Variables, resp); err != nil {
+		return fmt.Errorf("failed to execute a query: %w", err)
+	}
+
+	return nil
+}
+
 // WorkflowPos represents a position in a workflow file.
 type WorkflowPos struct {
 	Path string
@@ -136,7 +206,27 @@ type AllowedEntry struct {
 // GlobalLintParams represents a set of lint parameters for global settings.
 type GlobalLintParams struct {
 	// NumWorkers is the number of workers for linters.
+	// The number of CPUs in the system is used if the value is not positive.
 	NumWorkers int64
+
+	// Workers is a worker pool that bounds the number of concurrent lints across workflows.
+	// If nil, LintWorkflowFilesContext creates a pool of NumWorkers workers that is shared by all of the workflow files.
+	// Share a pool to bound the number of concurrent lints across multiple calls.
+	Workers *semaphore.Weighted
+}
+
+// workers returns the worker pool of the parameters, or a new worker pool of NumWorkers workers if not set.
+func (p GlobalLintParams) workers() *semaphore.Weighted {
+	if p.Workers != nil {
+		return p.Workers
+	}
+
+	numWorkers := p.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = int64(runtime.NumCPU())
+	}
+
+	return semaphore.NewWeighted(numWorkers)
 }
 
 func (p GlobalLintParams) String() string {
@@ -182,6 +272,48 @@ type WorkflowLintParams struct {
 	// key is a repository ID (OWNER/NAME) or an action ID.
 	// value is an allowlist of commit hashes that are allowed to use.
 	HashAllowlist map[string][]AllowedEntry `yaml:"hash_allowlist,omitempty"`
+
+	// RequireSignedCommits is a flag to require commits that actions are pinned to be signed.
+	// If true, the linter reports actions pinned to unsigned commits or commits with invalid signatures.
+	RequireSignedCommits *bool `yaml:"require_signed_commits,omitempty"`
+
+	// SignedCommitExemptOwners is a list of action owners that are exempted from RequireSignedCommits.
+	SignedCommitExemptOwners []string `yaml:"signed_commit_exempt_owners,omitempty"`
+
+	// ExpectedSigners is a mapping of action owners or repository IDs (OWNER/NAME) to expected signers of the pinned commits.
+	// A signer is either a GitHub login, an email address, a GPG key ID, an SSH key fingerprint, or 'web-flow' (signed by GitHub).
+	// If no entry matches an action, a commit with any valid signature is accepted.
+	ExpectedSigners map[string][]string `yaml:"expected_signers,omitempty"`
+
+	// MinReleaseAge is the minimum number of days since the pinned commit or tag was published.
+	// If it is greater than zero, the linter reports actions that refer to a more recent commit or tag.
+	MinReleaseAge *int `yaml:"min_release_age,omitempty"`
+
+	// ReleaseAgeAllowlist is a list of commit hashes that are exempted from MinReleaseAge (e.g. emergency patches).
+	// Tag references are matched by the commit hash that the tag resolves to.
+	// key is a repository ID (OWNER/NAME) or an action ID.
+	ReleaseAgeAllowlist map[string][]AllowedEntry `yaml:"release_age_allowlist,omitempty"`
+
+	// MaxRepoInactivity is the maximum number of days without commits or releases in an action repository.
+	// If it is greater than zero, the linter warns about actions whose repositories have been inactive for longer.
+	MaxRepoInactivity *int `yaml:"max_repo_inactivity,omitempty"`
+
+	// DeprecatedRuntimes is a list of deprecated runtimes of actions (runs.using in action.yml). e.g. node16
+	// The linter reports actions that run on one of the runtimes. The check is disabled if the list is empty (default)
+	// since it reads action.yml of every referenced action from a clone of the action repository.
+	DeprecatedRuntimes []string `yaml:"deprecated_runtimes,omitempty"`
+
+	// RuleConfigs is a mapping of rule IDs to configurations of the rules. e.g. {"AA005": {"enabled": false}}
+	RuleConfigs map[string]RuleConfig `yaml:"rules,omitempty"`
+
+	// Host is a GitHub host (e.g. github.example.com) to resolve actions.
+	// If it is empty, the default host of the linter is used.
+	Host *string `yaml:"host,omitempty"`
+
+	// OwnerHosts is a mapping of action owners to GitHub hosts.
+	// This is useful for GitHub Enterprise Server instances that proxy actions on github.com.
+	// e.g. {"actions": "github.com"}
+	OwnerHosts map[string]string `yaml:"owner_hosts,omitempty"`
 }
 
 type WorkflowLintOption func(*WorkflowLintParams) error
@@ -259,6 +391,119 @@ func WithHashAllowlist(v map[string][]AllowedEntry) WorkflowLintOption {
 	}
 }
 
+func WithRequireSignedCommits(v bool) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		p.RequireSignedCommits = &v
+		return nil
+	}
+}
+
+func WithSignedCommitExemptOwners(v []string) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		for _, owner := range v {
+			if !slices.Contains(p.SignedCommitExemptOwners, owner) {
+				p.SignedCommitExemptOwners = append(p.SignedCommitExemptOwners, owner)
+			}
+		}
+
+		return nil
+	}
+}
+
+func WithExpectedSigners(v map[string][]string) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		p.ExpectedSigners = v
+		return nil
+	}
+}
+
+func WithMinReleaseAge(v int) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		if v < 0 {
+			return fmt.Errorf("min release age must be greater than or equal to zero: %d", v)
+		}
+
+		p.MinReleaseAge = &v
+		return nil
+	}
+}
+
+func WithReleaseAgeAllowlist(v map[string][]AllowedEntry) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		p.ReleaseAgeAllowlist = v
+		return nil
+	}
+}
+
+func WithMaxRepoInactivity(v int) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		if v < 0 {
+			return fmt.Errorf("max repository inactivity must be greater than or equal to zero: %d", v)
+		}
+
+		p.MaxRepoInactivity = &v
+		return nil
+	}
+}
+
+func WithDeprecatedRuntimes(v []string) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		p.DeprecatedRuntimes = v
+		return nil
+	}
+}
+
+func WithRuleConfigs(v map[string]RuleConfig) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		if p.RuleConfigs == nil {
+			p.RuleConfigs = map[string]RuleConfig{}
+		}
+
+		for id, config := range v {
+			p.RuleConfigs[id] = config
+		}
+
+		return nil
+	}
+}
+
+func WithDisabledRules(ids []string) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		if p.RuleConfigs == nil {
+			p.RuleConfigs = map[string]RuleConfig{}
+		}
+
+		for _, id := range ids {
+			config := p.RuleConfigs[id]
+			config.Enabled = boolPtr(false)
+			p.RuleConfigs[id] = config
+		}
+
+		return nil
+	}
+}
+
+func WithHost(v string) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		p.Host = &v
+		return nil
+	}
+}
+
+func WithOwnerHosts(v map[string]string) WorkflowLintOption {
+	return func(p *WorkflowLintParams) error {
+		if p.OwnerHosts == nil {
+			p.OwnerHosts = map[string]string{}
+		}
+
+		for owner, host := range v {
+			p.OwnerHosts[owner] = host
+		}
+
+		return nil
+	}
+}
+
 func NewWorkflowLintParams(opts ...WorkflowLintOption) (*WorkflowLintParams, error) {
 	var p WorkflowLintParams
 
@@ -292,6 +537,20 @@ func NewWorkflowLintParams(opts ...WorkflowLintOption) (*WorkflowLintParams, err
 		p.EnforceVerifiedOrganization = boolPtr(DefaultEnforceVerifiedOrg)
 	}
 
+	if p.RequireSignedCommits == nil {
+		p.RequireSignedCommits = boolPtr(DefaultRequireSignedCommits)
+	}
+
+	if p.MinReleaseAge == nil {
+		minReleaseAge := DefaultMinReleaseAge
+		p.MinReleaseAge = &minReleaseAge
+	}
+
+	if p.MaxRepoInactivity == nil {
+		maxRepoInactivity := DefaultMaxRepoInactivity
+		p.MaxRepoInactivity = &maxRepoInactivity
+	}
+
 	if p.CreatorAllowlist == nil {
 		p.CreatorAllowlist = []string{}
 	}
@@ -304,6 +563,30 @@ func NewWorkflowLintParams(opts ...WorkflowLintOption) (*WorkflowLintParams, err
 		p.HashAllowlist = map[string][]AllowedEntry{}
 	}
 
+	if p.SignedCommitExemptOwners == nil {
+		p.SignedCommitExemptOwners = []string{}
+	}
+
+	if p.ExpectedSigners == nil {
+		p.ExpectedSigners = map[string][]string{}
+	}
+
+	if p.ReleaseAgeAllowlist == nil {
+		p.ReleaseAgeAllowlist = map[string][]AllowedEntry{}
+	}
+
+	if p.DeprecatedRuntimes == nil {
+		p.DeprecatedRuntimes = []string{}
+	}
+
+	if p.RuleConfigs == nil {
+		p.RuleConfigs = map[string]RuleConfig{}
+	}
+
+	if p.OwnerHosts == nil {
+		p.OwnerHosts = map[string]string{}
+	}
+
 	return &p, nil
 }
 
@@ -346,6 +629,46 @@ func (p WorkflowLintParams) GetOptions() []WorkflowLintOption {
 		opts = append(opts, WithHashAllowlist(p.HashAllowlist))
 	}
 
+	if p.RequireSignedCommits != nil {
+		opts = append(opts, WithRequireSignedCommits(*p.RequireSignedCommits))
+	}
+
+	if len(p.SignedCommitExemptOwners) > 0 {
+		opts = append(opts, WithSignedCommitExemptOwners(p.SignedCommitExemptOwners))
+	}
+
+	if len(p.ExpectedSigners) > 0 {
+		opts = append(opts, WithExpectedSigners(p.ExpectedSigners))
+	}
+
+	if p.MinReleaseAge != nil {
+		opts = append(opts, WithMinReleaseAge(*p.MinReleaseAge))
+	}
+
+	if len(p.ReleaseAgeAllowlist) > 0 {
+		opts = append(opts, WithReleaseAgeAllowlist(p.ReleaseAgeAllowlist))
+	}
+
+	if p.MaxRepoInactivity != nil {
+		opts = append(opts, WithMaxRepoInactivity(*p.MaxRepoInactivity))
+	}
+
+	if p.DeprecatedRuntimes != nil {
+		opts = append(opts, WithDeprecatedRuntimes(p.DeprecatedRuntimes))
+	}
+
+	if len(p.RuleConfigs) > 0 {
+		opts = append(opts, WithRuleConfigs(p.RuleConfigs))
+	}
+
+	if p.Host != nil {
+		opts = append(opts, WithHost(*p.Host))
+	}
+
+	if len(p.OwnerHosts) > 0 {
+		opts = append(opts, WithOwnerHosts(p.OwnerHosts))
+	}
+
 	return opts
 }
 
@@ -370,6 +693,95 @@ func (p WorkflowLintParams) GetHashAllowlist(action Action) []AllowedEntry {
 	return nil
 }
 
+// IsTrustedAction returns true if an action is excluded from the rules of trust (e.g. pinning, verified organizations):
+// official actions, allowlisted actions, and actions of verified creators. It also returns the reason.
+func (p WorkflowLintParams) IsTrustedAction(action Action) (bool, string) {
+	if p.ExcludeOfficialActions != nil && *p.ExcludeOfficialActions && slices.Contains(OfficialCreators, action.Owner) {
+		return true, "official action"
+	}
+
+	if slices.Contains(p.CreatorAllowlist, action.Owner) {
+		return true, "allowlisted creator"
+	}
+
+	if slices.Contains(p.ActionAllowlist, action.RepoID()) {
+		return true, "allowlisted action"
+	}
+
+	if action.IsPinnedBySHA() {
+		for _, entry := range p.GetHashAllowlist(action) {
+			if entry.SHA == action.Ref {
+				return true, "pinned by allowlisted hash"
+			}
+		}
+	}
+
+	if p.ExcludeVerifiedCreators != nil && *p.ExcludeVerifiedCreators && slices.Contains(actionsByVerifiedCreators, action.RepoID()) {
+		return true, "verified creator"
+	}
+
+	return false, ""
+}
+
+// IsRuleEnabled returns true if the rule of the ID is enabled. Rules are enabled by default.
+func (p WorkflowLintParams) IsRuleEnabled(id string) bool {
+	config, exist := p.RuleConfigs[id]
+	if !exist || config.Enabled == nil {
+		return true
+	}
+
+	return *config.Enabled
+}
+
+// GetRuleSeverity returns a severity of the findings of a rule: the configured severity if exists, otherwise the default severity of the rule.
+func (p WorkflowLintParams) GetRuleSeverity(rule Rule) Severity {
+	if config, exist := p.RuleConfigs[rule.ID()]; exist && config.Severity != nil {
+		return *config.Severity
+	}
+
+	return rule.DefaultSeverity()
+}
+
+// GetReleaseAgeAllowlist returns an allowlist of commit hashes that are exempted from the minimum release age.
+func (p WorkflowLintParams) GetReleaseAgeAllowlist(action Action) []AllowedEntry {
+	if allowlist, exist := p.ReleaseAgeAllowlist[action.ID]; exist {
+		return allowlist
+	}
+
+	if allowlist, exist := p.ReleaseAgeAllowlist[action.RepoID()]; exist {
+		return allowlist
+	}
+
+	return []AllowedEntry{}
+}
+
+// GetExpectedSigners returns expected signers of the commits of the action.
+func (p WorkflowLintParams) GetExpectedSigners(action Action) []string {
+	if signers, exist := p.ExpectedSigners[action.RepoID()]; exist {
+		return signers
+	}
+
+	if signers, exist := p.ExpectedSigners[action.Owner]; exist {
+		return signers
+	}
+
+	return nil
+}
+
+// GetHost returns a GitHub host to resolve actions of the owner.
+// An empty string means the default host of the linter.
+func (p WorkflowLintParams) GetHost(owner string) string {
+	if host, exist := p.OwnerHosts[owner]; exist {
+		return host
+	}
+
+	if p.Host != nil {
+		return *p.Host
+	}
+
+	return ""
+}
+
 // WorkflowLintInfo represents the linting information of a workflow file.
 type WorkflowLintInfo struct {
 	// FilePath is an absolute path to the GitHub Actions workflow file.
@@ -383,6 +795,31 @@ type WorkflowLintInfo struct {
 
 	// RepoID is a repository ID (OWNER/NAME) of the project.
 	RepoID string
+
+	// Content is the body of the workflow file.
+	// If it is nil, the content is read from the FilePath.
+	Content []byte
+
+	// Rev is a Git revision that the workflow was read from.
+	// It is empty when the workflow was read from a working tree.
+	Rev string
+
+	// TargetUses is a set of positions of 'uses' values to be linted.
+	// If it is nil, all of the 'uses' values in the workflow are linted.
+	TargetUses workflow.PosSet
+
+	// Lockfile is a lockfile of the repository.
+	// If it is not nil, tag references are verified against the commit hashes recorded in the lockfile.
+	Lockfile *workflow.Lockfile
+}
+
+// IsTarget returns true if the 'uses' value is a linting target.
+func (wf WorkflowLintInfo) IsTarget(uses *actionlint.String) bool {
+	if wf.TargetUses == nil {
+		return true
+	}
+
+	return uses != nil && wf.TargetUses.Contains(uses.Pos)
 }
 
 // RelPath returns a relative path to the project root.
@@ -425,30 +862,213 @@ type Linter interface {
 
 	// LintWorkflowFiles lints workflow files with a context.
 	LintWorkflowFilesContext(ctx context.Context, globalLintParams GlobalLintParams, wfInfoList []WorkflowLintInfo) ([]*Error, error)
+
+	// PrefetchContext fetches metadata of the repositories and the owners of actions in batched queries.
+	PrefetchContext(ctx context.Context, actions []Action) error
+
+	// DescribeActionContext returns information of an action repository at the ref of the action.
+	DescribeActionContext(ctx context.Context, action Action) (*ActionDetail, error)
+
+	// ResolveTagContext returns a commit hash that the tag reference of an action resolves to.
+	// The tag is resolved without caches.
+	ResolveTagContext(ctx context.Context, action Action) (string, error)
+
+	// Rules returns the rules of the linter in the applied order.
+	Rules() []Rule
+
+	// RegisterRule adds a custom rule to the linter.
+	RegisterRule(rule Rule) error
+
+	// ValidateParams returns an error if the parameters refer to rules that are not registered to the linter.
+	ValidateParams(params *WorkflowLintParams) error
+
+	// IsOffline returns true if the linter does not access the network.
+	// Lookups of actions return ErrOffline in the offline mode.
+	IsOffline() bool
+}
+
+// Params is a set of parameters to create a Linter instance.
+type Params struct {
+	// Logger is a logger for the linter.
+	Logger *slog.Logger
+
+	// Host is the default GitHub host that the clients connect to.
+	Host string
+
+	// GqlClient is a GitHub GraphQL client for the default host.
+	GqlClient *api.GraphQLClient
+
+	// UncachedGqlClient is a GitHub GraphQL client for the default host that does not cache responses.
+	// It resolves tags that are locked by lockfiles. GqlClient is used if it is nil.
+	UncachedGqlClient *api.GraphQLClient
+
+	// GdExecutor is a git-describe executor for the default host.
+	GdExecutor executor.Executor
+
+	// Resolver is a git tag resolver for the default host.
+	Resolver *resolver.Resolver
+
+	// NewHostClients creates clients for a host other than the default host.
+	// If it is nil, the linter resolves actions only with the default host.
+	NewHostClients NewHostClientsFunc
+
+	// Advisories is a local database of security advisories to match actions against.
+	// If it is nil, actions are not checked against advisories.
+	Advisories *advisory.Database
+
+	// Rules is a list of custom rules that are applied in addition to the built-in rules.
+	Rules []Rule
+
+	// Offline disables the rules that require network access and the prefetch of action metadata.
+	// Actions are matched against the advisories only by their refs.
+	Offline bool
+}
+
+// New creates a new Linter instance.
+// Clients are not required in the offline mode.
+func New(params *Params) (Linter, error) {
+	if params.GqlClient == nil && !params.Offline {
+		return nil, fmt.Errorf("required a GraphQL client")
+	}
+
+	if params.Resolver == nil && !params.Offline {
+		return nil, fmt.Errorf("required a resolver")
+	}
+
+	logger := params.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	defaultClients := &HostClients{
+		GqlClient:         params.GqlClient,
+		UncachedGqlClient: params.UncachedGqlClient,
+		GdExecutor:        params.GdExecutor,
+		Resolver:          params.Resolver,
+	}
+
+	l := &linter{
+		logger:     logger,
+		clientPool: newHostClientPool(params.Host, defaultClients, params.NewHostClients),
+		advisories: params.Advisories,
+		lookups:    newLookups(),
+		offline:    params.Offline,
+	}
+
+	rules, err := NewRuleRegistry(append(newBuiltinRules(l), params.Rules...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register rules: %w", err)
+	}
+	l.rules = rules
+
+	return l, nil
 }
 
 // NewLinter creates a new Linter instance.
+// It panics if the GraphQL client or the resolver is nil.
+//
+// Deprecated: Use New, which also accepts advisories, custom rules, and the offline mode, and returns errors.
 func NewLinter(logger *slog.Logger, gqlClient *api.GraphQLClient, gdExecutor executor.Executor, resolver *resolver.Resolver) Linter {
-	return &linter{
-		logger:     logger,
-		gqlClient:  gqlClient,
-		gdExecutor: gdExecutor,
-		resolver:   resolver,
+	l, err := New(&Params{
+		Logger:     logger,
+		GqlClient:  gqlClient,
+		GdExecutor: gdExecutor,
+		Resolver:   resolver,
+	})
+	if err != nil {
+		panic(err)
 	}
+
+	return l
 }
 
 type linter struct {
 	logger     *slog.Logger
-	gqlClient  *api.GraphQLClient
-	gdExecutor executor.Executor
-	resolver   *resolver.Resolver
+	clientPool *hostClientPool
+	advisories *advisory.Database
+	rules      *RuleRegistry
+
+	// lookups memoizes lookups of repositories, owners, and refs so that each of them is queried once per linter.
+	lookups *lookups
+
+	// offline skips the rules that require network access
+	offline bool
+}
+
+// Rules returns the rules of the linter in the applied order.
+func (l *linter) Rules() []Rule {
+	return l.rules.Rules()
+}
+
+// RegisterRule adds a custom rule to the linter.
+func (l *linter) RegisterRule(rule Rule) error {
+	return l.rules.Register(rule)
+}
+
+// ValidateParams returns an error if the parameters refer to rules that are not registered to the linter.
+func (l *linter) ValidateParams(params *WorkflowLintParams) error {
+	if params == nil {
+		return nil
+	}
+
+	if err := l.rules.ValidateRuleConfigs(params.RuleConfigs); err != nil {
+		return fmt.Errorf("invalid rule configurations: %w", err)
+	}
+
+	return nil
+}
+
+// IsOffline returns true if the linter does not access the network.
+func (l *linter) IsOffline() bool {
+	return l.offline
+}
+
+// getClients returns the clients for the host. It returns ErrOffline in the offline mode.
+func (l *linter) getClients(host string) (*HostClients, error) {
+	if l.offline {
+		return nil, ErrOffline
+	}
+
+	return l.clientPool.get(host)
 }
 
-func (l linter) getQueryParams(variables map[string]interface{}) *QueryParams {
+func (l *linter) getQueryParams(host string, variables map[string]interface{}) (*QueryParams, error) {
+	clients, err := l.getClients(host)
+	if err != nil {
+		return nil, err
+	}
+
 	return &QueryParams{
-		Client:    l.gqlClient,
+		Client:    clients.GqlClient,
 		Variables: variables,
+	}, nil
+}
+
+// getUncachedQueryParams returns the query parameters with a client that does not cache responses.
+func (l *linter) getUncachedQueryParams(host string, variables map[string]interface{}) (*QueryParams, error) {
+	clients, err := l.getClients(host)
+	if err != nil {
+		return nil, err
 	}
+
+	client := clients.UncachedGqlClient
+	if client == nil {
+		client = clients.GqlClient
+	}
+
+	return &QueryParams{
+		Client:    client,
+		Variables: variables,
+	}, nil
+}
+
+func (l *linter) getResolver(host string) (*resolver.Resolver, error) {
+	clients, err := l.getClients(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.Resolver, nil
 }
 
 type Result struct {
@@ -458,13 +1078,17 @@ type Result struct {
 
 // LintWorkflow lints a workflow content.
 // content must be the body of the workflow file.
-func (l linter) LintWorkflow(done <-chan interface{}, globalLintParams GlobalLintParams, wfLintInfo WorkflowLintInfo, content []byte) ([]<-chan Result, error) {
+func (l *linter) LintWorkflow(done <-chan interface{}, globalLintParams GlobalLintParams, wfLintInfo WorkflowLintInfo, content []byte) ([]<-chan Result, error) {
 	return l.LintWorkflowContext(context.Background(), done, globalLintParams, wfLintInfo, content)
 }
 
 // LintWorkflowContext lints a workflow content with a context.
 // content must be the body of the workflow file.
-func (l linter) LintWorkflowContext(ctx context.Context, done <-chan interface{}, globalLintParams GlobalLintParams, wfLintInfo WorkflowLintInfo, content []byte) ([]<-chan Result, error) {
+func (l *linter) LintWorkflowContext(ctx context.Context, done <-chan interface{}, globalLintParams GlobalLintParams, wfLintInfo WorkflowLintInfo, content []byte) ([]<-chan Result, error) {
+	if err := l.ValidateParams(wfLintInfo.Params); err != nil {
+		return nil, err
+	}
+
 	executorChannels := make([]<-chan Result, 0)
 
 	workflow, parseErrors := actionlint.Parse(content)
@@ -499,27 +1123,30 @@ func (l linter) LintWorkflowContext(ctx context.Context, done <-chan interface{}
 		slog.Any("workflow-lint-info", wfLintInfo),
 	)
 
-	sem := semaphore.NewWeighted(globalLintParams.NumWorkers)
+	workers := globalLintParams.workers()
 
 	runLinter := func(done <-chan interface{}, step *actionlint.Step) <-chan Result {
 		resultStream := make(chan Result)
 
-		if err := sem.Acquire(ctx, 1); err != nil {
-			resultStream <- Result{LintErrors: nil, RuntimeError: err}
-		}
-
 		go func() {
 			defer close(resultStream)
 
+			// wait for a worker without blocking the caller: the acquisition is canceled with the context
+			if err := workers.Acquire(ctx, 1); err != nil {
+				select {
+				case <-done:
+				case resultStream <- Result{LintErrors: nil, RuntimeError: fmt.Errorf("failed to acquire a worker: %w", err)}:
+				}
+				return
+			}
+
 			lintErrors := make([]*Error, 0)
 			switch exec := step.Exec.(type) {
 			case *actionlint.ExecAction:
-				if lintError := l.lintJobUses(ctx, exec.Uses, wfLintInfo); lintError != nil {
-					lintErrors = append(lintErrors, lintError)
-				}
+				lintErrors = append(lintErrors, l.lintJobUses(ctx, exec.Uses, wfLintInfo)...)
 			}
 
-			sem.Release(1)
+			workers.Release(1)
 
 			select {
 			case <-done:
@@ -531,10 +1158,15 @@ func (l linter) LintWorkflowContext(ctx context.Context, done <-chan interface{}
 		return resultStream
 	}
 
-	for name, job := range workflow.Jobs {
-		logger.Debug("linting a job", slog.String("job", name))
+	for _, job := range sortedJobs(workflow) {
+		logger.Debug("linting a job", slog.String("job", job.ID.Value))
 
 		for _, step := range job.Steps {
+			if exec, ok := step.Exec.(*actionlint.ExecAction); ok && !wfLintInfo.IsTarget(exec.Uses) {
+				logger.Debug("skip linting a step", slog.String("uses", exec.Uses.Value), slog.String("reason", "unchanged"))
+				continue
+			}
+
 			executorChannels = append(executorChannels, runLinter(done, step))
 		}
 	}
@@ -542,23 +1174,32 @@ func (l linter) LintWorkflowContext(ctx context.Context, done <-chan interface{}
 	return executorChannels, nil
 }
 
-func (l *linter) checkVerifiedOrg(login string, params *WorkflowLintParams) error {
-	if params.EnforceVerifiedOrganization == nil || !*params.EnforceVerifiedOrganization {
-		return nil
-	}
+// isVerifiedOrganization returns true if the owner of an action is a verified organization.
+// Actions owned by users are regarded as verified since users cannot be verified.
+func (l *linter) isVerifiedOrganization(a Action) (bool, error) {
+	return l.lookups.verifiedOwners.Do(ownerKey(a.Host, a.Owner), func() (bool, error) {
+		return l.fetchIsVerifiedOrganization(a)
+	})
+}
 
+func (l *linter) fetchIsVerifiedOrganization(a Action) (bool, error) {
+	login := a.Owner
 	variables := map[string]interface{}{
 		"login": githubv4.String(login),
 	}
+	queryParams, err := l.getQueryParams(a.Host, variables)
+	if err != nil {
+		return false, err
+	}
 
 	var queryLoginUser struct {
 		User struct {
 			Login string
 		} `graphql:"user(login: $login)"`
 	}
-	if err := query(&queryLoginUser, l.getQueryParams(variables)); err != nil {
+	if err := query(&queryLoginUser, queryParams); err != nil {
 		if !strings.Contains(err.Error(), "Could not resolve to") {
-			return err
+			return false, err
 		}
 	}
 
@@ -567,7 +1208,7 @@ func (l *linter) checkVerifiedOrg(login string, params *WorkflowLintParams) erro
 			slog.String("login", queryLoginUser.User.Login),
 			slog.String("reason", "user found"),
 		)
-		return nil
+		return true, nil
 	}
 
 	var queryLoginOrg struct {
@@ -575,11 +1216,11 @@ func (l *linter) checkVerifiedOrg(login string, params *WorkflowLintParams) erro
 			Login string
 		} `graphql:"organization(login: $login)"`
 	}
-	if err := query(&queryLoginOrg, l.getQueryParams(variables)); err != nil {
-		return fmt.Errorf("failed to execute a query: %w", err)
+	if err := query(&queryLoginOrg, queryParams); err != nil {
+		return false, fmt.Errorf("failed to execute a query: %w", err)
 	}
 	if queryLoginOrg.Organization.Login == "" {
-		return fmt.Errorf("organization not found: %s", login)
+		return false, fmt.Errorf("organization not found: %s", login)
 	}
 
 	var queryIsVerified struct {
@@ -587,20 +1228,26 @@ func (l *linter) checkVerifiedOrg(login string, params *WorkflowLintParams) erro
 			IsVerified bool
 		} `graphql:"organization(login: $login)"`
 	}
-	variables = map[string]interface{}{
-		"login": githubv4.String(login),
-	}
-	if err := query(&queryIsVerified, l.getQueryParams(variables)); err != nil {
-		return fmt.Errorf("failed to execute a query: %w", err)
-	}
-	if !queryIsVerified.Organization.IsVerified {
-		return fmt.Errorf("organization is not verified: %s", login)
+	if err := query(&queryIsVerified, queryParams); err != nil {
+		return false, fmt.Errorf("failed to execute a query: %w", err)
 	}
 
-	return nil
+	return queryIsVerified.Organization.IsVerified, nil
 }
 
 func (l *linter) isArchivedAction(a Action) (bool, *time.Time, error) {
+	status, err := l.lookups.archiveStatuses.Do(repoKey(a.Host, a.Owner, a.Name), func() (archiveStatus, error) {
+		archived, archivedAt, err := l.fetchArchiveStatus(a)
+		return archiveStatus{archived: archived, archivedAt: archivedAt}, err
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	return status.archived, status.archivedAt, nil
+}
+
+func (l *linter) fetchArchiveStatus(a Action) (bool, *time.Time, error) {
 	var queryIsArchived struct {
 		Repository struct {
 			IsArchived bool
@@ -611,69 +1258,146 @@ func (l *linter) isArchivedAction(a Action) (bool, *time.Time, error) {
 		"owner": githubv4.String(a.Owner),
 		"name":  githubv4.String(a.Name),
 	}
+	queryParams, err := l.getQueryParams(a.Host, variables)
+	if err != nil {
+		return false, nil, err
+	}
 
-	if err := query(&queryIsArchived, l.getQueryParams(variables)); err != nil {
+	if err := query(&queryIsArchived, queryParams); err != nil {
 		return false, nil, err
 	}
 
 	return queryIsArchived.Repository.IsArchived, queryIsArchived.Repository.ArchivedAt, nil
 }
 
-func (l linter) isVerifiedCreator(a Action) (bool, error) {
-	if slices.Contains(actionsByVerifiedCreators, a.RepoID()) {
-		return true, nil
-	}
+func (l *linter) resolveGitTag(ctx context.Context, repo repository.Repository, tag string) (*resolver.GitTag, error) {
+	return l.lookups.gitTags.DoContext(ctx, refKey(repo.Host, repo.Owner, repo.Name, tag), func(ctx context.Context) (*resolver.GitTag, error) {
+		r, err := l.getResolver(repo.Host)
+		if err != nil {
+			return nil, err
+		}
 
-	return false, nil
+		return r.ResolveFromTagContext(ctx, repo, tag)
+	})
 }
 
-func (l linter) isAllowlisted(action Action, params *WorkflowLintParams) (bool, string) {
-	if slices.Contains(params.CreatorAllowlist, action.Owner) {
-		return true, "allowlisted creator"
+func (l *linter) resolveGitTagNamesFromSha(ctx context.Context, repo repository.Repository, ref string) ([]string, error) {
+	return l.lookups.tagNames.DoContext(ctx, refKey(repo.Host, repo.Owner, repo.Name, ref), func(ctx context.Context) ([]string, error) {
+		return l.fetchGitTagNamesFromSha(ctx, repo, ref)
+	})
+}
+
+func (l *linter) fetchGitTagNamesFromSha(ctx context.Context, repo repository.Repository, ref string) ([]string, error) {
+	r, err := l.getResolver(repo.Host)
+	if err != nil {
+		return nil, err
 	}
 
-	if slices.Contains(params.ActionAllowlist, action.RepoID()) {
-		return true, "allowlisted action"
+	gitTags, err := r.ResolveFromHashContext(ctx, repo, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve git tags from a ref: repo=%s/%s, ref=%s, error=%w",
+			repo.Owner, repo.Name, ref, err)
 	}
 
-	if !action.IsPinnedBySHA() {
-		return false, "not allowlisted"
+	tagNames := make([]string, 0, len(gitTags))
+	for _, gitTag := range gitTags {
+		tagNames = append(tagNames, gitTag.Tag)
 	}
 
-	allowlist := params.GetHashAllowlist(action)
-	for _, entry := range allowlist {
-		if entry.SHA != action.Ref {
-			continue
-		}
+	return tagNames, nil
+}
 
-		return true, "pinned by allowlisted hash"
-	}
+// ResolveTagContext returns a commit hash that the tag reference of an action resolves to.
+// Tags are resolved without caches to detect moved tags.
+func (l *linter) ResolveTagContext(ctx context.Context, action Action) (string, error) {
+	return l.resolveLockedTag(ctx, action)
+}
 
-	return false, "not allowlisted"
+// resolveLockedTag returns a commit hash that the tag reference of an action resolves to.
+// The tag is resolved without the caches of the resolver and the responses so that a moved tag is detected immediately.
+func (l *linter) resolveLockedTag(ctx context.Context, action Action) (string, error) {
+	return l.lookups.lockedTags.DoContext(ctx, refKey(action.Host, action.Owner, action.Name, action.Ref), func(context.Context) (string, error) {
+		return l.fetchLockedTag(action)
+	})
 }
 
-func (l linter) resolveGitTagNamesFromSha(ctx context.Context, repo repository.Repository, ref string) ([]string, error) {
-	gitTags, err := l.resolver.ResolveFromHashContext(ctx, repo, ref)
+func (l *linter) fetchLockedTag(action Action) (string, error) {
+	var queryRef struct {
+		Repository struct {
+			Ref *struct {
+				Target struct {
+					Oid string
+					Tag struct {
+						Target struct {
+							Commit struct {
+								Oid string
+							} `graphql:"... on Commit"`
+						}
+					} `graphql:"... on Tag"`
+				}
+			} `graphql:"ref(qualifiedName: $qualifiedName)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":         githubv4.String(action.Owner),
+		"name":          githubv4.String(action.Name),
+		"qualifiedName": githubv4.String("refs/tags/" + action.Ref),
+	}
+	queryParams, err := l.getUncachedQueryParams(action.Host, variables)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve git tags from a ref: repo=%s/%s, ref=%s, error=%w",
-			repo.Owner, repo.Name, ref, err)
+		return "", err
 	}
 
-	tagNames := make([]string, 0, len(gitTags))
-	for _, gitTag := range gitTags {
-		tagNames = append(tagNames, gitTag.Tag)
+	if err := query(&queryRef, queryParams); err != nil {
+		return "", err
 	}
 
-	return tagNames, nil
+	ref := queryRef.Repository.Ref
+	if ref == nil {
+		return "", fmt.Errorf("tag not found: repo=%s, tag=%s", action.RepoID(), action.Ref)
+	}
+
+	// an annotated tag points to a tag object that points to the commit
+	if commitHash := ref.Target.Tag.Target.Commit.Oid; commitHash != "" {
+		return commitHash, nil
+	}
+
+	return ref.Target.Oid, nil
 }
 
-func (l linter) lintJobUses(ctx context.Context, uses *actionlint.String, wfLintInfo WorkflowLintInfo) *Error {
-	if uses == nil {
+// checkLockedTag checks if a tag reference resolves to the commit hash recorded in the lockfile.
+// A tag that resolves to a different commit hash has been moved, possibly by a compromised repository.
+func (l *linter) checkLockedTag(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) *Error {
+	if wfLintInfo.Lockfile == nil || action.IsPinnedBySHA() {
+		return nil
+	}
+
+	lockedSHA, exist := wfLintInfo.Lockfile.Lookup(action.HostOr(l.clientPool.defaultHost), action.RepoID(), action.Ref)
+	if !exist {
+		l.logger.Debug("tag is not locked", slog.String("action", action.String()))
 		return nil
 	}
 
-	logValidActionFound := func(logger *slog.Logger, reason string, args ...any) {
-		logger.Debug("valid action found", append([]any{slog.String("reason", reason)}, args...)...)
+	commitHash, err := l.resolveLockedTag(ctx, action)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to resolve git tag: %s", err.Error()),
+			wfLintInfo, action.Pos, KindRuntimeError)
+	}
+
+	if commitHash != lockedSHA {
+		return NewError(
+			fmt.Sprintf("tag resolves to a different commit than the lockfile: action=%s, tag=%s, locked=%s, actual=%s",
+				action.RepoID(), action.Ref, shortenHash(lockedSHA), shortenHash(commitHash)),
+			wfLintInfo, action.Pos, KindTagMoved)
+	}
+
+	return nil
+}
+
+func (l *linter) lintJobUses(ctx context.Context, uses *actionlint.String, wfLintInfo WorkflowLintInfo) []*Error {
+	if uses == nil {
+		return nil
 	}
 
 	l.logger.Debug("linting a step", slog.String("uses", uses.Value))
@@ -689,16 +1413,16 @@ func (l linter) lintJobUses(ctx context.Context, uses *actionlint.String, wfLint
 	case 2:
 		relPath, err := wfLintInfo.RelPath()
 		if err != nil {
-			return newLintError(
+			return []*Error{newLintError(
 				fmt.Sprintf("failed to get relative path: %s", err.Error()),
-				wfLintInfo.FilePath, wfLintInfo, uses.Pos, KindRuntimeError)
+				wfLintInfo.FilePath, wfLintInfo, uses.Pos, KindRuntimeError)}
 		}
 
 		workflowPos := WorkflowPos{Path: relPath, Pos: uses.Pos}
 
 		action, err := ParseActionUses(uses.Value)
 		if err != nil {
-			return newLintError(err.Error(), relPath, wfLintInfo, workflowPos.Pos, KindUnexpectedValue)
+			return []*Error{newLintError(err.Error(), relPath, wfLintInfo, workflowPos.Pos, KindUnexpectedValue)}
 		}
 
 		logger := l.logger.With(
@@ -712,163 +1436,59 @@ func (l linter) lintJobUses(ctx context.Context, uses *actionlint.String, wfLint
 		}
 
 		params := wfLintInfo.Params
-
-		archived, archivedAt, err := l.isArchivedAction(*action)
-		if err != nil {
-			return newLintError(
-				fmt.Sprintf("failed to check if the action is archived: %s", err.Error()),
-				relPath, wfLintInfo, workflowPos.Pos, KindRuntimeError)
-		}
-		if archived {
-			if !*params.AllowArchivedRepo {
-				return newLintError(
-					fmt.Sprintf("archived action found: repo=%s, archived-at=%s", action.RepoID(), archivedAt.Format("2006-01-02")),
-					relPath, wfLintInfo, workflowPos.Pos, KindArchivedActionUsed)
-			}
-
-			logger.Warn("archived action found", slog.String("archived-at", archivedAt.String()))
-		}
-
-		if *params.ExcludeOfficialActions && slices.Contains(OfficialCreators, action.Owner) {
-			logValidActionFound(logger, "official action")
-			return nil
-		}
-
-		allowlisted, reason := l.isAllowlisted(*action, params)
-		if allowlisted {
-			if action.IsPinnedBySHA() {
-				refShortHash := shortenHash(action.Ref)
-				tagNames, err := l.resolveGitTagNamesFromSha(ctx, action.Repository(), action.Ref)
-				if err != nil {
-					return newLintError(
-						fmt.Sprintf("failed to resolve git tags from sha: %s", err.Error()),
-						relPath, wfLintInfo, workflowPos.Pos, KindRuntimeError)
-				}
-
-				logValidActionFound(logger, reason,
-					slog.String("hash", refShortHash),
-					slog.Any("tag", tagNames),
-				)
-			} else {
-				gitTag, err := l.resolver.ResolveFromTagContext(ctx, action.Repository(), action.Ref)
-				if err != nil {
-					return newLintError(
-						fmt.Sprintf("failed to resolve git tag: %s", err.Error()),
-						relPath, wfLintInfo, workflowPos.Pos, KindRuntimeError)
-				}
-
-				logValidActionFound(logger, reason,
-					slog.String("tag", gitTag.Tag),
-					slog.String("hash", gitTag.CommitHash),
-				)
-			}
-
-			return nil
-		}
-
-		if *params.ExcludeVerifiedCreators {
-			verifiedDev, err := l.isVerifiedCreator(*action)
-			if err != nil {
-				return newLintError(
-					fmt.Sprintf("failed to check if the creator is verified: %s", err.Error()),
-					relPath, wfLintInfo, workflowPos.Pos, KindRuntimeError)
+		action.Host = params.GetHost(action.Owner)
+		action.Pos = uses.Pos
+
+		lintErrors := make([]*Error, 0)
+		for _, rule := range l.rules.Rules() {
+			if !params.IsRuleEnabled(rule.ID()) {
+				logger.Debug("skip a rule", slog.String("rule", rule.ID()), slog.String("reason", "disabled"))
+				continue
 			}
-			if verifiedDev {
-				logValidActionFound(logger, "verified creator")
-				return nil
+			if l.offline && requiresNetwork(rule) {
+				logger.Debug("skip a rule", slog.String("rule", rule.ID()), slog.String("reason", "offline"))
+				continue
 			}
-		}
-
-		err = l.checkVerifiedOrg(action.Owner, params)
-		if err != nil {
-			return newLintError(
-				fmt.Sprintf("failed to check if the owner is verified: %s", err.Error()),
-				relPath, wfLintInfo, workflowPos.Pos, KindRuntimeError)
-		}
 
-		refPos := &actionlint.Pos{
-			Line: uses.Pos.Line,
-			Col:  uses.Pos.Col + len(action.ID) + 1,
-		}
-
-		if action.IsPinnedBySHA() {
-			tagNames, err := l.resolveGitTagNamesFromSha(ctx, action.Repository(), action.Ref)
-			if err != nil {
-				return newLintError(
-					fmt.Sprintf("failed to resolve git tags: %s", err.Error()),
-					relPath, wfLintInfo, refPos, KindRuntimeError)
-			}
-			tagsStr := strings.Join(tagNames, ", ")
-			refShortHash := shortenHash(action.Ref)
+			for _, lintError := range rule.Check(ctx, *action, wfLintInfo) {
+				lintError.RuleID = rule.ID()
 
-			if *params.AllowOnlyAllowlistedHash {
-				allowedEntries := wfLintInfo.Params.GetHashAllowlist(*action)
-				allowlist := make([]string, 0, len(allowedEntries))
-
-				for _, entry := range allowedEntries {
-					var comment string
-					if entry.Comment != nil {
-						comment = strings.TrimSpace(replaceNewlines(*entry.Comment))
-					}
-
-					entryTagNames, err := l.resolveGitTagNamesFromSha(ctx, action.Repository(), entry.SHA)
-					if err != nil {
-						return newLintError(
-							fmt.Sprintf("failed to resolve git tags: %s", err.Error()),
-							relPath, wfLintInfo, refPos, KindRuntimeError)
-					}
-					entryTagsStr := strings.Join(entryTagNames, ", ")
-					entryShortSHA := shortenHash(entry.SHA)
-
-					var allowlistEntry string
-					if comment == "" {
-						allowlistEntry = fmt.Sprintf("%s(%s)", entryShortSHA, entryTagsStr)
+				if lintError.Severity == "" {
+					if lintError.LintError.Kind == string(KindRuntimeError) {
+						lintError.Severity = SeverityError
 					} else {
-						allowlistEntry = fmt.Sprintf("%s(%s: %s)", entryShortSHA, entryTagsStr, truncateString(comment, maxCommentLen))
+						lintError.Severity = params.GetRuleSeverity(rule)
 					}
-
-					allowlist = append(allowlist, allowlistEntry)
 				}
 
-				return newLintError(
-					fmt.Sprintf("invalid ref value: action=%s, sha=%s(%s), allowlist=%v", action.ID, refShortHash, tagsStr, allowlist),
-					relPath, wfLintInfo, refPos, "SHA is not allowlisted",
-				)
+				lintErrors = append(lintErrors, lintError)
 			}
-
-			logValidActionFound(logger, reason,
-				slog.String("hash", refShortHash),
-				slog.Any("tag", tagNames),
-			)
-
-			return nil
 		}
 
-		if *params.EnforcePinHash {
-			return newLintError(
-				fmt.Sprintf("invalid ref value: action=%s, expected=SHA, actual=%s", action.RepoID(), action.Ref),
-				relPath, wfLintInfo, refPos, KindUnpinned,
-			)
+		if len(lintErrors) == 0 {
+			logger.Debug("valid action found")
 		}
 
-		logger.Warn("unpinned action found", slog.String("reason", "not pinned by hash"))
-
-		return nil
+		return lintErrors
 
 	default:
-		return newLintError(
+		return []*Error{newLintError(
 			fmt.Sprintf("invalid uses value: %s", uses.Pos.String()),
-			wfLintInfo.FilePath, wfLintInfo, uses.Pos, KindUnexpectedValue)
+			wfLintInfo.FilePath, wfLintInfo, uses.Pos, KindUnexpectedValue)}
 	}
 }
 
 // LintWorkflowFile lints a workflow file.
-func (l linter) LintWorkflowFile(done <-chan interface{}, globalLintParams GlobalLintParams, wfLintInfo WorkflowLintInfo) ([]<-chan Result, error) {
+func (l *linter) LintWorkflowFile(done <-chan interface{}, globalLintParams GlobalLintParams, wfLintInfo WorkflowLintInfo) ([]<-chan Result, error) {
 	return l.LintWorkflowFileContext(context.Background(), done, globalLintParams, wfLintInfo)
 }
 
 // LintWorkflowFileContext lints a workflow file with a context.
-func (l linter) LintWorkflowFileContext(ctx context.Context, done <-chan interface{}, globalLintParams GlobalLintParams, wfLintInfo WorkflowLintInfo) ([]<-chan Result, error) {
+func (l *linter) LintWorkflowFileContext(ctx context.Context, done <-chan interface{}, globalLintParams GlobalLintParams, wfLintInfo WorkflowLintInfo) ([]<-chan Result, error) {
+	if wfLintInfo.Content != nil {
+		return l.LintWorkflowContext(ctx, done, globalLintParams, wfLintInfo, wfLintInfo.Content)
+	}
+
 	bytes, err := os.ReadFile(wfLintInfo.FilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read a workflow file: path=%s, error=%w", wfLintInfo.FilePath, err)
@@ -878,16 +1498,25 @@ func (l linter) LintWorkflowFileContext(ctx context.Context, done <-chan interfa
 }
 
 // LintWorkflowFiles lints workflow files.
-func (l linter) LintWorkflowFiles(globalLintParams GlobalLintParams, wfLintInfoList []WorkflowLintInfo) ([]*Error, error) {
+func (l *linter) LintWorkflowFiles(globalLintParams GlobalLintParams, wfLintInfoList []WorkflowLintInfo) ([]*Error, error) {
 	return l.LintWorkflowFilesContext(context.Background(), globalLintParams, wfLintInfoList)
 }
 
 // LintWorkflowFiles lints workflow files with a context.
-func (l linter) LintWorkflowFilesContext(ctx context.Context, globalLintParams GlobalLintParams, wfLintInfoList []WorkflowLintInfo) ([]*Error, error) {
+func (l *linter) LintWorkflowFilesContext(ctx context.Context, globalLintParams GlobalLintParams, wfLintInfoList []WorkflowLintInfo) ([]*Error, error) {
 	executorChannels := make([]<-chan Result, 0)
 	done := make(chan interface{})
 	defer close(done)
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// share a worker pool across the workflow files to bound the total number of concurrent lints
+	globalLintParams.Workers = globalLintParams.workers()
+
+	// fetch metadata of all of the repositories and the owners up front instead of a query per step
+	l.prefetchWorkflows(ctx, wfLintInfoList)
+
 	lintErrors := make([]*Error, 0)
 
 	for _, wfLintInfo := range wfLintInfoList {
@@ -907,11 +1536,45 @@ func (l linter) LintWorkflowFilesContext(ctx context.Context, globalLintParams G
 
 		lintErrors = append(lintErrors, result.LintErrors...)
 	}
-	// TODO: sort errors
+
+	SortErrors(lintErrors)
 
 	return lintErrors, nil
 }
 
+// SortErrors sorts findings in a deterministic order: repository, path, line, column, and rule.
+func SortErrors(lintErrors []*Error) {
+	slices.SortStableFunc(lintErrors, func(a, b *Error) int {
+		return cmp.Or(
+			strings.Compare(a.RepoID, b.RepoID),
+			strings.Compare(a.Rev, b.Rev),
+			strings.Compare(a.LintError.Filepath, b.LintError.Filepath),
+			cmp.Compare(a.LintError.Line, b.LintError.Line),
+			cmp.Compare(a.LintError.Column, b.LintError.Column),
+			strings.Compare(a.RuleID, b.RuleID),
+			strings.Compare(a.LintError.Kind, b.LintError.Kind),
+			strings.Compare(a.LintError.Message, b.LintError.Message),
+		)
+	})
+}
+
+// sortedJobs returns the jobs of a workflow in the order of their positions in the source.
+func sortedJobs(workflow *actionlint.Workflow) []*actionlint.Job {
+	jobs := make([]*actionlint.Job, 0, len(workflow.Jobs))
+	for _, job := range workflow.Jobs {
+		jobs = append(jobs, job)
+	}
+
+	slices.SortFunc(jobs, func(a, b *actionlint.Job) int {
+		return cmp.Or(
+			cmp.Compare(a.Pos.Line, b.Pos.Line),
+			cmp.Compare(a.Pos.Col, b.Pos.Col),
+		)
+	})
+
+	return jobs
+}
+
 func ReadLintOptions(c *workflow.ActionArmorConfigFile) ([]WorkflowLintOption, error) {
 	if c == nil {
 		return nil, fmt.Errorf("required a config file")
@@ -944,10 +1607,20 @@ func toLintParams(data []byte) (*WorkflowLintParams, error) {
 		}
 	}
 
+	for action, allowlist := range params.ReleaseAgeAllowlist {
+		for i := range allowlist {
+			params.ReleaseAgeAllowlist[action][i].SHA = strings.TrimSpace(params.ReleaseAgeAllowlist[action][i].SHA)
+		}
+	}
+
 	return &params, nil
 }
 
 func newLintError(msg, relPath string, wfLintInfo WorkflowLintInfo, pos *actionlint.Pos, kind ErrorKind) *Error {
+	if pos == nil {
+		pos = &actionlint.Pos{}
+	}
+
 	return &Error{
 		LintError: actionlint.Error{
 			Message:  msg,
@@ -958,5 +1631,9 @@ func newLintError(msg, relPath string, wfLintInfo WorkflowLintInfo, pos *actionl
 		},
 		WorkflowAbsFilePath: wfLintInfo.FilePath,
 		Project:             wfLintInfo.Project,
+		RepoID:              wfLintInfo.RepoID,
+		Source:              wfLintInfo.Content,
+		Rev:                 wfLintInfo.Rev,
+		Severity:            SeverityError,
 	}
 }
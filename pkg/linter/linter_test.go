@@ -15,8 +15,10 @@ import (
 	"github.com/rhysd/actionlint"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/thombashi/gh-actionarmor/pkg/advisory"
 	gitdescribe "github.com/thombashi/gh-git-describe/pkg/executor"
 	"github.com/thombashi/gh-taghash/pkg/resolver"
+	"golang.org/x/sync/semaphore"
 )
 
 var testLogger = slog.New(
@@ -43,6 +45,339 @@ func TestWorkflowPos_String(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestFormatDisplayPath(t *testing.T) {
+	const repoID = "owner/repo"
+	const filePath = ".github/workflows/ci.yml"
+
+	testCases := []struct {
+		name string
+		rev  string
+		want string
+	}{
+		{
+			name: "working tree",
+			rev:  "",
+			want: "owner/repo/.github/workflows/ci.yml",
+		},
+		{
+			name: "revision",
+			rev:  "main",
+			want: "owner/repo@main:.github/workflows/ci.yml",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			a.Equal(tc.want, FormatDisplayPath(repoID, tc.rev, filePath))
+
+			// findings and usages of the same workflow are displayed with the same path
+			lintError := Error{RepoID: repoID, Rev: tc.rev, LintError: actionlint.Error{Filepath: filePath}}
+			a.Equal(tc.want, lintError.DisplayPath())
+
+			usage := ActionUsage{RepoID: repoID, Rev: tc.rev, FilePath: filePath}
+			a.Equal(tc.want, usage.DisplayPath())
+		})
+	}
+}
+
+func TestWorkflowLintParams_GetHost(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	testCases := []struct {
+		name  string
+		opts  []WorkflowLintOption
+		owner string
+		want  string
+	}{
+		{
+			name:  "default host",
+			opts:  nil,
+			owner: "actions",
+			want:  "",
+		},
+		{
+			name:  "host",
+			opts:  []WorkflowLintOption{WithHost("github.example.com")},
+			owner: "actions",
+			want:  "github.example.com",
+		},
+		{
+			name: "owner host",
+			opts: []WorkflowLintOption{
+				WithHost("github.example.com"),
+				WithOwnerHosts(map[string]string{"actions": "github.com"}),
+			},
+			owner: "actions",
+			want:  "github.com",
+		},
+		{
+			name: "owner host: not matched",
+			opts: []WorkflowLintOption{
+				WithHost("github.example.com"),
+				WithOwnerHosts(map[string]string{"actions": "github.com"}),
+			},
+			owner: "my-org",
+			want:  "github.example.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		params, err := NewWorkflowLintParams(tc.opts...)
+		r.NoError(err)
+
+		a.Equal(tc.want, params.GetHost(tc.owner), tc.name)
+	}
+}
+
+func TestWorkflowLintParams_GetExpectedSigners(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	params, err := NewWorkflowLintParams(
+		WithExpectedSigners(map[string][]string{
+			"actions":          {"web-flow"},
+			"actions/checkout": {"octocat"},
+		}),
+	)
+	r.NoError(err)
+
+	testCases := []struct {
+		name   string
+		action Action
+		want   []string
+	}{
+		{
+			name:   "repository",
+			action: Action{Owner: "actions", Name: "checkout"},
+			want:   []string{"octocat"},
+		},
+		{
+			name:   "owner",
+			action: Action{Owner: "actions", Name: "setup-go"},
+			want:   []string{"web-flow"},
+		},
+		{
+			name:   "not matched",
+			action: Action{Owner: "my-org", Name: "my-action"},
+			want:   nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		a.Equal(tc.want, params.GetExpectedSigners(tc.action), tc.name)
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	a := assert.New(t)
+
+	newError := func(repoID, path string, line, col int, ruleID string) *Error {
+		return &Error{
+			LintError: actionlint.Error{Filepath: path, Line: line, Column: col},
+			RepoID:    repoID,
+			RuleID:    ruleID,
+		}
+	}
+
+	lintErrors := []*Error{
+		newError("owner/b", ".github/workflows/a.yml", 1, 1, "AA001"),
+		newError("owner/a", ".github/workflows/b.yml", 1, 1, "AA001"),
+		newError("owner/a", ".github/workflows/a.yml", 10, 1, "AA001"),
+		newError("owner/a", ".github/workflows/a.yml", 2, 15, "AA002"),
+		newError("owner/a", ".github/workflows/a.yml", 2, 15, "AA001"),
+		newError("owner/a", ".github/workflows/a.yml", 2, 9, "AA004"),
+	}
+
+	SortErrors(lintErrors)
+
+	want := []*Error{
+		newError("owner/a", ".github/workflows/a.yml", 2, 9, "AA004"),
+		newError("owner/a", ".github/workflows/a.yml", 2, 15, "AA001"),
+		newError("owner/a", ".github/workflows/a.yml", 2, 15, "AA002"),
+		newError("owner/a", ".github/workflows/a.yml", 10, 1, "AA001"),
+		newError("owner/a", ".github/workflows/b.yml", 1, 1, "AA001"),
+		newError("owner/b", ".github/workflows/a.yml", 1, 1, "AA001"),
+	}
+	a.Equal(want, lintErrors)
+}
+
+func TestSortedJobs(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	content := dedent.Dedent(`
+		on: push
+		jobs:
+		  zeta:
+		    runs-on: ubuntu-latest
+		    steps:
+		      - run: echo
+		  alpha:
+		    runs-on: ubuntu-latest
+		    steps:
+		      - run: echo
+		  mu:
+		    runs-on: ubuntu-latest
+		    steps:
+		      - run: echo
+		`)
+
+	workflow, errs := actionlint.Parse([]byte(content))
+	r.Empty(errs)
+
+	ids := []string{}
+	for _, job := range sortedJobs(workflow) {
+		ids = append(ids, job.ID.Value)
+	}
+	a.Equal([]string{"zeta", "alpha", "mu"}, ids)
+}
+
+func TestLintWorkflowContext_UnknownRule(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	registry, err := NewRuleRegistry()
+	r.NoError(err)
+	l := linter{logger: testLogger, rules: registry}
+
+	params, err := NewWorkflowLintParams(WithDisabledRules([]string{"AA01"}))
+	r.NoError(err)
+
+	_, err = l.LintWorkflowContext(context.Background(), nil, GlobalLintParams{}, WorkflowLintInfo{Params: params, RepoID: "owner/repo"}, []byte("on: push\n"))
+	a.EqualError(err, "invalid rule configurations: unknown rule IDs: AA01")
+}
+
+func TestLintWorkflowContext_Workers(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	registry, err := NewRuleRegistry()
+	r.NoError(err)
+	l := linter{logger: testLogger, rules: registry}
+
+	params, err := NewWorkflowLintParams()
+	r.NoError(err)
+
+	content := []byte(dedent.Dedent(`
+		name: Test Workflow
+		on: push
+		jobs:
+		  test:
+		    runs-on: ubuntu-latest
+		    steps:
+		      - uses: ./local-action
+		      - uses: docker://alpine:3
+		`))
+
+	// all of the workers are busy: acquisitions must be canceled with the context instead of blocking
+	workers := semaphore.NewWeighted(1)
+	r.NoError(workers.Acquire(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan interface{})
+	defer close(done)
+
+	channels, err := l.LintWorkflowContext(ctx, done, GlobalLintParams{Workers: workers}, WorkflowLintInfo{Params: params, RepoID: "owner/repo"}, content)
+	r.NoError(err)
+	a.Len(channels, 2)
+
+	cancel()
+
+	var numRuntimeErrors int
+	for result := range fanIn(done, channels...) {
+		if result.RuntimeError != nil {
+			numRuntimeErrors++
+		}
+	}
+	a.Equal(2, numRuntimeErrors)
+
+	// workers are released after linting
+	workers.Release(1)
+	channels, err = l.LintWorkflowContext(context.Background(), done, GlobalLintParams{Workers: workers}, WorkflowLintInfo{Params: params, RepoID: "owner/repo"}, content)
+	r.NoError(err)
+
+	for result := range fanIn(done, channels...) {
+		a.NoError(result.RuntimeError)
+		a.Empty(result.LintErrors)
+	}
+	a.True(workers.TryAcquire(1))
+}
+
+func TestLintWorkflowContext_Offline(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	advisories := advisory.NewDatabase()
+	advisories.Add(&advisory.Advisory{
+		ID: "GHSA-mrrh-fwg8-r2c3",
+		Affected: []advisory.Affected{
+			{Package: "tj-actions/changed-files", Versions: []string{"v45.0.7"}},
+		},
+	})
+
+	// the linter has no clients: the test panics if any rule requires network access
+	l := &linter{logger: testLogger, advisories: advisories, lookups: newLookups(), offline: true}
+	registry, err := NewRuleRegistry(newBuiltinRules(l)...)
+	r.NoError(err)
+	l.rules = registry
+
+	params, err := NewWorkflowLintParams(WithExcludeOfficialActions(false), WithEnforceVerifiedOrganization(true))
+	r.NoError(err)
+
+	content := []byte(dedent.Dedent(`
+		name: Test Workflow
+		on: push
+		jobs:
+		  test:
+		    runs-on: ubuntu-latest
+		    steps:
+		      - uses: tj-actions/changed-files@v45.0.7
+		      - uses: actions/checkout@v4
+		`))
+
+	done := make(chan interface{})
+	defer close(done)
+
+	channels, err := l.LintWorkflowContext(context.Background(), done, GlobalLintParams{}, WorkflowLintInfo{Params: params, RepoID: "owner/repo"}, content)
+	r.NoError(err)
+
+	ruleIDs := make([]string, 0)
+	for result := range fanIn(done, channels...) {
+		r.NoError(result.RuntimeError)
+
+		for _, lerr := range result.LintErrors {
+			ruleIDs = append(ruleIDs, lerr.RuleID)
+		}
+	}
+	a.ElementsMatch([]string{RuleIDPinHash, RuleIDVulnerableAction, RuleIDPinHash}, ruleIDs)
+}
+
+func TestNew_Offline(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	// clients are not required in the offline mode
+	l, err := New(&Params{Logger: testLogger, Offline: true})
+	r.NoError(err)
+	a.True(l.IsOffline())
+
+	action := Action{Host: "github.com", Owner: "actions", Name: "checkout", ID: "actions/checkout", Ref: "v4"}
+
+	_, err = l.DescribeActionContext(context.Background(), action)
+	a.ErrorIs(err, ErrOffline)
+
+	_, err = l.ResolveTagContext(context.Background(), action)
+	a.ErrorIs(err, ErrOffline)
+
+	_, _, err = l.(*linter).isArchivedAction(action)
+	a.ErrorIs(err, ErrOffline)
+
+	a.NoError(l.PrefetchContext(context.Background(), []Action{action}))
+}
+
 func TestLintWorkflowContext(t *testing.T) {
 	a := assert.New(t)
 	r := require.New(t)
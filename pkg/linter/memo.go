@@ -0,0 +1,109 @@
+package linter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thombashi/gh-actionarmor/pkg/workflow"
+	"github.com/thombashi/gh-taghash/pkg/resolver"
+	"golang.org/x/sync/singleflight"
+)
+
+// memo memoizes results of lookups in process.
+// Concurrent lookups of the same key are coalesced into a single call.
+// Errors are not memoized so that a failed lookup is retried by a later call.
+type memo[V any] struct {
+	group  singleflight.Group
+	values sync.Map
+}
+
+// Do returns the memoized value of the key, or calls fn and memoizes the result.
+func (m *memo[V]) Do(key string, fn func() (V, error)) (V, error) {
+	return m.DoContext(context.Background(), key, func(context.Context) (V, error) {
+		return fn()
+	})
+}
+
+// DoContext returns the memoized value of the key, or calls fn and memoizes the result.
+//
+// fn is called with a context that is detached from the cancellation of ctx: the call is shared by
+// the concurrent callers of the key, so the cancellation of the caller that started the call must not fail the others.
+// A canceled caller returns the error of ctx without waiting for the shared call, which runs to completion.
+func (m *memo[V]) DoContext(ctx context.Context, key string, fn func(ctx context.Context) (V, error)) (V, error) {
+	var zero V
+
+	if v, exist := m.values.Load(key); exist {
+		return v.(V), nil
+	}
+
+	sharedCtx := context.WithoutCancel(ctx)
+	resultCh := m.group.DoChan(key, func() (any, error) {
+		if v, exist := m.values.Load(key); exist {
+			return v, nil
+		}
+
+		v, err := fn(sharedCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		m.values.Store(key, v)
+
+		return v, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case result := <-resultCh:
+		if result.Err != nil {
+			return zero, result.Err
+		}
+
+		return result.Val.(V), nil
+	}
+}
+
+// Store memoizes a value of the key. It is used to prime the memo with prefetched values.
+func (m *memo[V]) Store(key string, v V) {
+	m.values.Store(key, v)
+}
+
+// archiveStatus represents the archive status of a repository.
+type archiveStatus struct {
+	archived   bool
+	archivedAt *time.Time
+}
+
+// lookups is a set of memoized lookups of the linter for a run.
+// Repositories are keyed by HOST/OWNER/NAME, refs by HOST/OWNER/NAME@REF, and owners by HOST/OWNER.
+type lookups struct {
+	archiveStatuses memo[archiveStatus]
+	verifiedOwners  memo[bool]
+	gitTags         memo[*resolver.GitTag]
+	lockedTags      memo[string]
+	tagNames        memo[[]string]
+	tags            memo[[]string]
+	activities      memo[*RepoActivity]
+	releases        memo[*Release]
+	signatures      memo[*CommitSignature]
+	metadata        memo[*workflow.ActionMetadata]
+}
+
+func newLookups() *lookups {
+	return &lookups{}
+}
+
+func ownerKey(host, owner string) string {
+	return fmt.Sprintf("%s/%s", host, owner)
+}
+
+func repoKey(host, owner, name string) string {
+	return fmt.Sprintf("%s/%s/%s", host, owner, name)
+}
+
+func refKey(host, owner, name, ref string) string {
+	return fmt.Sprintf("%s/%s/%s@%s", host, owner, name, ref)
+}
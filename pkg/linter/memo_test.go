@@ -0,0 +1,134 @@
+package linter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemo_Do(t *testing.T) {
+	a := assert.New(t)
+
+	var m memo[string]
+	var numCalls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			v, err := m.Do(refKey("github.com", "actions", "checkout", "v4"), func() (string, error) {
+				numCalls.Add(1)
+				<-release
+				return "11bd71901bbe5b1630ceea73d27597364c9af683", nil
+			})
+			a.NoError(err)
+			a.Equal("11bd71901bbe5b1630ceea73d27597364c9af683", v)
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	a.Equal(int32(1), numCalls.Load())
+
+	// memoized
+	v, err := m.Do(refKey("github.com", "actions", "checkout", "v4"), func() (string, error) {
+		numCalls.Add(1)
+		return "", nil
+	})
+	a.NoError(err)
+	a.Equal("11bd71901bbe5b1630ceea73d27597364c9af683", v)
+	a.Equal(int32(1), numCalls.Load())
+
+	// another key
+	_, err = m.Do(refKey("github.com", "actions", "checkout", "v3"), func() (string, error) {
+		numCalls.Add(1)
+		return "", nil
+	})
+	a.NoError(err)
+	a.Equal(int32(2), numCalls.Load())
+}
+
+func TestMemo_DoError(t *testing.T) {
+	a := assert.New(t)
+
+	var m memo[bool]
+	var numCalls int
+
+	_, err := m.Do(ownerKey("github.com", "octocat"), func() (bool, error) {
+		numCalls++
+		return false, errors.New("temporary failure")
+	})
+	a.EqualError(err, "temporary failure")
+
+	// errors are not memoized
+	v, err := m.Do(ownerKey("github.com", "octocat"), func() (bool, error) {
+		numCalls++
+		return true, nil
+	})
+	a.NoError(err)
+	a.True(v)
+	a.Equal(2, numCalls)
+
+	m.Store(ownerKey("github.com", "github"), true)
+	v, err = m.Do(ownerKey("github.com", "github"), func() (bool, error) {
+		return false, errors.New("must not be called")
+	})
+	a.NoError(err)
+	a.True(v)
+}
+
+func TestMemo_DoContext(t *testing.T) {
+	a := assert.New(t)
+
+	var m memo[string]
+	key := refKey("github.com", "actions", "checkout", "v4")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var sharedCtxErr error
+
+	// the first caller starts the shared call and is canceled while the call is running
+	ctx, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan error)
+	go func() {
+		_, err := m.DoContext(ctx, key, func(ctx context.Context) (string, error) {
+			close(started)
+			<-release
+			sharedCtxErr = ctx.Err()
+			return "11bd71901bbe5b1630ceea73d27597364c9af683", nil
+		})
+		firstDone <- err
+	}()
+	<-started
+
+	// the second caller waits for the shared call
+	secondDone := make(chan error)
+	var v string
+	go func() {
+		var err error
+		v, err = m.DoContext(context.Background(), key, func(ctx context.Context) (string, error) {
+			return "", errors.New("must not be called")
+		})
+		secondDone <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	a.ErrorIs(<-firstDone, context.Canceled)
+
+	close(release)
+	a.NoError(<-secondDone)
+	a.Equal("11bd71901bbe5b1630ceea73d27597364c9af683", v)
+
+	// the shared call is not canceled by the cancellation of the first caller
+	a.NoError(sharedCtxErr)
+}
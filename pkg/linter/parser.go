@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/rhysd/actionlint"
 
 	"github.com/thombashi/gh-taghash/pkg/resolver"
 )
@@ -16,30 +17,78 @@ type Action struct {
 	Owner string
 	Name  string
 	Ref   string
+
+	// Host is a GitHub host of the action repository. An empty string means the default host.
+	Host string
+
+	// Pos is a position of the 'uses' value in the workflow. It is nil if the action was not parsed from a workflow.
+	Pos *actionlint.Pos
 }
 
 func (a Action) String() string {
 	return fmt.Sprintf("%s/%s@%s", a.Owner, a.Name, a.Ref)
 }
 
+// RefPos returns a position of the ref in the 'uses' value: OWNER/REPO@REF
+func (a Action) RefPos() *actionlint.Pos {
+	if a.Pos == nil {
+		return nil
+	}
+
+	return &actionlint.Pos{
+		Line: a.Pos.Line,
+		Col:  a.Pos.Col + len(a.ID) + 1,
+	}
+}
+
 // RepoID returns a string of 'owner/repo'.
 func (a Action) RepoID() string {
 	return fmt.Sprintf("%s/%s", a.Owner, a.Name)
 }
 
+// FullRepoID returns a string of 'host/owner/repo'. It returns 'owner/repo' if the host is unknown.
+func (a Action) FullRepoID() string {
+	if a.Host == "" {
+		return a.RepoID()
+	}
+
+	return fmt.Sprintf("%s/%s", a.Host, a.RepoID())
+}
+
+// HostOr returns the host of the action, or defaultHost if the host is unknown.
+func (a Action) HostOr(defaultHost string) string {
+	if a.Host == "" {
+		return defaultHost
+	}
+
+	return a.Host
+}
+
 func (a Action) Repository() repository.Repository {
 	return repository.Repository{
+		Host:  a.Host,
 		Owner: a.Owner,
 		Name:  a.Name,
 	}
 }
 
+// SubPath returns a path in the action repository (e.g. a directory of an action or a reusable workflow file).
+// It returns an empty string if the action is at the root of the repository.
+func (a Action) SubPath() string {
+	return strings.TrimPrefix(strings.TrimPrefix(a.ID, a.RepoID()), "/")
+}
+
 // IsLocalReusableWorkflows returns true if the action is a local reusable workflows.
 // ref: https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#jobsjob_iduses
 func (a Action) IsLocalReusableWorkflows() bool {
 	return strings.HasPrefix(a.ID, "./.github/workflows/")
 }
 
+// IsReusableWorkflow returns true if the action is a reusable workflow in a remote repository.
+func (a Action) IsReusableWorkflow() bool {
+	return strings.HasPrefix(a.SubPath(), ".github/workflows/")
+}
+
 // IsPinnedBySHA returns true if the 'Ref' value is a SHA hash.
 func (a Action) IsPinnedBySHA() bool {
 	return resolver.IsSHA(a.Ref)
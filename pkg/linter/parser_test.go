@@ -3,6 +3,7 @@ package linter
 import (
 	"testing"
 
+	"github.com/rhysd/actionlint"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -138,6 +139,33 @@ func TestIsLocalReusableWorkflows(t *testing.T) {
 	}
 }
 
+func TestFullRepoID(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		Host string
+		Want string
+	}{
+		{
+			Host: "github.example.com",
+			Want: "github.example.com/actions/checkout",
+		},
+		{
+			Host: "",
+			Want: "actions/checkout",
+		},
+	}
+
+	for _, tc := range testCases {
+		action := Action{
+			Host:  tc.Host,
+			Owner: "actions",
+			Name:  "checkout",
+		}
+		a.Equal(tc.Want, action.FullRepoID())
+	}
+}
+
 func TestIsPinnedBySHA(t *testing.T) {
 	a := assert.New(t)
 
@@ -169,3 +197,15 @@ func TestIsPinnedBySHA(t *testing.T) {
 		a.Equal(tc.Want, got)
 	}
 }
+
+func TestActionRefPos(t *testing.T) {
+	a := assert.New(t)
+
+	action := Action{
+		ID:  "actions/checkout",
+		Pos: &actionlint.Pos{Line: 10, Col: 15},
+	}
+	a.Equal(&actionlint.Pos{Line: 10, Col: 32}, action.RefPos())
+
+	a.Nil(Action{ID: "actions/checkout"}.RefPos())
+}
@@ -0,0 +1,291 @@
+package linter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// maxPrefetchNodes is the maximum number of aliased nodes in a batched query.
+const maxPrefetchNodes = 50
+
+// prefetchNode is a repository or an owner to be fetched in a batched query.
+type prefetchNode struct {
+	// Owner is an owner login of the node.
+	Owner string
+
+	// Name is a repository name of the node. It is empty for owner nodes.
+	Name string
+}
+
+func (n prefetchNode) isRepository() bool {
+	return n.Name != ""
+}
+
+// repositoryMetadata is a response of a repository node of a batched query.
+type repositoryMetadata struct {
+	IsArchived    bool       `json:"isArchived"`
+	ArchivedAt    *time.Time `json:"archivedAt"`
+	PushedAt      *time.Time `json:"pushedAt"`
+	LatestRelease *struct {
+		PublishedAt *time.Time `json:"publishedAt"`
+	} `json:"latestRelease"`
+}
+
+// ownerMetadata is a response of an owner node of a batched query.
+type ownerMetadata struct {
+	TypeName   string `json:"__typename"`
+	IsVerified bool   `json:"isVerified"`
+}
+
+// buildPrefetchQuery builds a batched GraphQL query of aliased nodes: rN for repositories and oN for owners.
+func buildPrefetchQuery(nodes []prefetchNode) (string, map[string]interface{}) {
+	params := make([]string, 0, len(nodes)*2)
+	fields := make([]string, 0, len(nodes))
+	variables := make(map[string]interface{}, len(nodes)*2)
+
+	for i, node := range nodes {
+		if node.isRepository() {
+			params = append(params, fmt.Sprintf("$owner%d: String!", i), fmt.Sprintf("$name%d: String!", i))
+			fields = append(fields, fmt.Sprintf(
+				"r%d: repository(owner: $owner%d, name: $name%d) { isArchived archivedAt pushedAt latestRelease { publishedAt } }",
+				i, i, i))
+			variables[fmt.Sprintf("owner%d", i)] = node.Owner
+			variables[fmt.Sprintf("name%d", i)] = node.Name
+
+			continue
+		}
+
+		params = append(params, fmt.Sprintf("$login%d: String!", i))
+		fields = append(fields, fmt.Sprintf(
+			"o%d: repositoryOwner(login: $login%d) { __typename ... on Organization { isVerified } }",
+			i, i))
+		variables[fmt.Sprintf("login%d", i)] = node.Owner
+	}
+
+	query := fmt.Sprintf("query(%s) {\n  %s\n}", strings.Join(params, ", "), strings.Join(fields, "\n  "))
+
+	return query, variables
+}
+
+// primeLookups stores the results of a batched query to the memoized lookups.
+// Nodes that are not found are skipped, so that they are looked up one by one later and reported with errors.
+func (lu *lookups) primeLookups(host string, nodes []prefetchNode, resp map[string]json.RawMessage) (int, error) {
+	var numPrimed int
+
+	for i, node := range nodes {
+		if node.isRepository() {
+			data, exist := resp[fmt.Sprintf("r%d", i)]
+			if !exist || string(data) == "null" {
+				continue
+			}
+
+			var repo repositoryMetadata
+			if err := json.Unmarshal(data, &repo); err != nil {
+				return numPrimed, fmt.Errorf("failed to decode a repository: repo=%s/%s, error=%w", node.Owner, node.Name, err)
+			}
+
+			key := repoKey(host, node.Owner, node.Name)
+			lu.archiveStatuses.Store(key, archiveStatus{archived: repo.IsArchived, archivedAt: repo.ArchivedAt})
+
+			activity := &RepoActivity{
+				IsArchived: repo.IsArchived,
+				PushedAt:   repo.PushedAt,
+			}
+			if repo.LatestRelease != nil {
+				activity.LatestReleasePublishedAt = repo.LatestRelease.PublishedAt
+			}
+			lu.activities.Store(key, activity)
+
+			numPrimed++
+
+			continue
+		}
+
+		data, exist := resp[fmt.Sprintf("o%d", i)]
+		if !exist || string(data) == "null" {
+			continue
+		}
+
+		var owner ownerMetadata
+		if err := json.Unmarshal(data, &owner); err != nil {
+			return numPrimed, fmt.Errorf("failed to decode an owner: owner=%s, error=%w", node.Owner, err)
+		}
+
+		switch owner.TypeName {
+		case "User":
+			// users are regarded as verified since users cannot be verified
+			lu.verifiedOwners.Store(ownerKey(host, node.Owner), true)
+		case "Organization":
+			lu.verifiedOwners.Store(ownerKey(host, node.Owner), owner.IsVerified)
+		default:
+			continue
+		}
+
+		numPrimed++
+	}
+
+	return numPrimed, nil
+}
+
+// prefetchNodeSet is a set of nodes to be prefetched per host.
+type prefetchNodeSet struct {
+	hostNodes map[string][]prefetchNode
+	seen      map[string]struct{}
+}
+
+func newPrefetchNodeSet() *prefetchNodeSet {
+	return &prefetchNodeSet{
+		hostNodes: map[string][]prefetchNode{},
+		seen:      map[string]struct{}{},
+	}
+}
+
+// add adds the repository node and/or the owner node of an action.
+func (s *prefetchNodeSet) add(action Action, repo, owner bool) {
+	if action.IsLocalReusableWorkflows() || action.Owner == "" || action.Name == "" {
+		return
+	}
+
+	nodes := make([]prefetchNode, 0, 2)
+	if repo {
+		nodes = append(nodes, prefetchNode{Owner: action.Owner, Name: action.Name})
+	}
+	if owner {
+		nodes = append(nodes, prefetchNode{Owner: action.Owner})
+	}
+
+	for _, node := range nodes {
+		key := repoKey(action.Host, node.Owner, node.Name)
+		if _, exist := s.seen[key]; exist {
+			continue
+		}
+		s.seen[key] = struct{}{}
+
+		s.hostNodes[action.Host] = append(s.hostNodes[action.Host], node)
+	}
+}
+
+// PrefetchContext fetches metadata of the repositories and the owners of actions in batched GraphQL queries
+// and memoizes the results, so that the following lookups of the actions do not issue a query per action.
+// Failures of prefetching are not fatal: the actions are looked up one by one later.
+// Nothing is fetched in the offline mode.
+func (l *linter) PrefetchContext(ctx context.Context, actions []Action) error {
+	if l.offline {
+		l.logger.Debug("skip prefetching", slog.String("reason", "offline"))
+		return nil
+	}
+
+	nodeSet := newPrefetchNodeSet()
+	for _, action := range actions {
+		nodeSet.add(action, true, true)
+	}
+
+	return l.prefetch(ctx, nodeSet)
+}
+
+func (l *linter) prefetch(ctx context.Context, nodeSet *prefetchNodeSet) error {
+	var errs []error
+
+	for host, nodes := range nodeSet.hostNodes {
+		for chunk := range slices.Chunk(nodes, maxPrefetchNodes) {
+			query, variables := buildPrefetchQuery(chunk)
+			queryParams, err := l.getQueryParams(host, variables)
+			if err != nil {
+				errs = append(errs, err)
+				break
+			}
+
+			resp := map[string]json.RawMessage{}
+			err = queryRaw(ctx, query, &resp, queryParams)
+
+			// a GraphQL error is returned with partial data if some of the nodes are not found
+			var gqlErr *api.GraphQLError
+			if err != nil && !errors.As(err, &gqlErr) {
+				errs = append(errs, fmt.Errorf("failed to prefetch metadata: host=%s, error=%w", host, err))
+				continue
+			}
+			if gqlErr != nil {
+				l.logger.Debug("partial prefetch results", slog.String("host", host), slog.Any("error", gqlErr))
+			}
+
+			numPrimed, err := l.lookups.primeLookups(host, chunk, resp)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			l.logger.Debug("prefetched metadata",
+				slog.String("host", host),
+				slog.Int("nodes", len(chunk)),
+				slog.Int("primed", numPrimed),
+			)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// prefetchWorkflows prefetches metadata of the actions used in workflows.
+// Only the metadata that the enabled rules look up is fetched: the repositories for the archived action rule
+// and the inactive repository rule, and the owners of untrusted actions for the verified organization rule.
+func (l *linter) prefetchWorkflows(ctx context.Context, wfLintInfoList []WorkflowLintInfo) {
+	if l.offline {
+		return
+	}
+
+	if err := l.prefetch(ctx, l.collectPrefetchNodes(wfLintInfoList)); err != nil {
+		l.logger.Warn("failed to prefetch metadata of actions", slog.Any("error", err))
+	}
+}
+
+// collectPrefetchNodes returns the nodes of the actions used in workflows that the enabled rules look up.
+func (l *linter) collectPrefetchNodes(wfLintInfoList []WorkflowLintInfo) *prefetchNodeSet {
+	nodeSet := newPrefetchNodeSet()
+
+	for _, wfLintInfo := range wfLintInfoList {
+		params := wfLintInfo.Params
+		if params == nil {
+			continue
+		}
+
+		checkArchived := params.IsRuleEnabled(RuleIDArchivedAction)
+		checkInactive := params.IsRuleEnabled(RuleIDInactiveRepo) && params.MaxRepoInactivity != nil && *params.MaxRepoInactivity > 0
+		checkVerified := params.IsRuleEnabled(RuleIDVerifiedOrg) && params.EnforceVerifiedOrganization != nil && *params.EnforceVerifiedOrganization
+		if !checkArchived && !checkInactive && !checkVerified {
+			continue
+		}
+
+		content := wfLintInfo.Content
+		if content == nil {
+			var err error
+
+			content, err = os.ReadFile(wfLintInfo.FilePath)
+			if err != nil {
+				continue
+			}
+		}
+
+		usages, err := ListActionUsages(wfLintInfo, content)
+		if err != nil {
+			l.logger.Debug("skip prefetching a workflow", slog.String("path", wfLintInfo.FilePath), slog.Any("error", err))
+			continue
+		}
+
+		for _, usage := range usages {
+			action := usage.Action
+			trusted, _ := params.IsTrustedAction(action)
+
+			nodeSet.add(action, checkArchived || checkInactive, checkVerified && !trusted)
+		}
+	}
+
+	return nodeSet
+}
@@ -0,0 +1,166 @@
+package linter
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lithammer/dedent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPrefetchQuery(t *testing.T) {
+	a := assert.New(t)
+
+	query, variables := buildPrefetchQuery([]prefetchNode{
+		{Owner: "actions", Name: "checkout"},
+		{Owner: "actions"},
+	})
+
+	a.Equal(`query($owner0: String!, $name0: String!, $login1: String!) {
+  r0: repository(owner: $owner0, name: $name0) { isArchived archivedAt pushedAt latestRelease { publishedAt } }
+  o1: repositoryOwner(login: $login1) { __typename ... on Organization { isVerified } }
+}`, query)
+	a.Equal(map[string]interface{}{
+		"owner0": "actions",
+		"name0":  "checkout",
+		"login1": "actions",
+	}, variables)
+}
+
+func TestPrimeLookups(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	const host = "github.com"
+	nodes := []prefetchNode{
+		{Owner: "actions", Name: "checkout"},
+		{Owner: "actions"},
+		{Owner: "octocat", Name: "archived"},
+		{Owner: "octocat"},
+		{Owner: "unknown", Name: "unknown"},
+		{Owner: "unknown"},
+	}
+	resp := map[string]json.RawMessage{}
+	r.NoError(json.Unmarshal([]byte(`{
+		"r0": {"isArchived": false, "archivedAt": null, "pushedAt": "2024-10-01T00:00:00Z", "latestRelease": {"publishedAt": "2024-09-01T00:00:00Z"}},
+		"o1": {"__typename": "Organization", "isVerified": true},
+		"r2": {"isArchived": true, "archivedAt": "2023-01-01T00:00:00Z", "pushedAt": "2022-12-01T00:00:00Z", "latestRelease": null},
+		"o3": {"__typename": "User"},
+		"r4": null,
+		"o5": null
+	}`), &resp))
+
+	lu := newLookups()
+	numPrimed, err := lu.primeLookups(host, nodes, resp)
+	r.NoError(err)
+	a.Equal(4, numPrimed)
+
+	notCalled := func() (bool, error) { return false, errors.New("must not be called") }
+
+	verified, err := lu.verifiedOwners.Do(ownerKey(host, "actions"), notCalled)
+	a.NoError(err)
+	a.True(verified)
+
+	verified, err = lu.verifiedOwners.Do(ownerKey(host, "octocat"), notCalled)
+	a.NoError(err)
+	a.True(verified)
+
+	status, err := lu.archiveStatuses.Do(repoKey(host, "octocat", "archived"), func() (archiveStatus, error) {
+		return archiveStatus{}, errors.New("must not be called")
+	})
+	a.NoError(err)
+	a.True(status.archived)
+	a.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), *status.archivedAt)
+
+	activity, err := lu.activities.Do(repoKey(host, "actions", "checkout"), func() (*RepoActivity, error) {
+		return nil, errors.New("must not be called")
+	})
+	a.NoError(err)
+	a.False(activity.IsArchived)
+	a.Equal(time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), *activity.PushedAt)
+	a.Equal(time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), *activity.LatestReleasePublishedAt)
+
+	// nodes that are not found are looked up one by one
+	_, err = lu.verifiedOwners.Do(ownerKey(host, "unknown"), notCalled)
+	a.EqualError(err, "must not be called")
+}
+
+func TestCollectPrefetchNodes(t *testing.T) {
+	content := []byte(dedent.Dedent(`
+		name: CI
+		on: push
+		jobs:
+		  test:
+		    runs-on: ubuntu-latest
+		    steps:
+		      - uses: actions/checkout@v4
+		      - uses: tj-actions/changed-files@v45
+		`))
+
+	testCases := []struct {
+		name string
+		opts []WorkflowLintOption
+		want []prefetchNode
+	}{
+		{
+			name: "repositories of all actions and owners of untrusted actions",
+			opts: []WorkflowLintOption{WithEnforceVerifiedOrganization(true)},
+			want: []prefetchNode{
+				{Owner: "actions", Name: "checkout"},
+				{Owner: "tj-actions", Name: "changed-files"},
+				{Owner: "tj-actions"},
+			},
+		},
+		{
+			name: "no owners if organizations are not required to be verified",
+			opts: []WorkflowLintOption{WithEnforceVerifiedOrganization(false)},
+			want: []prefetchNode{
+				{Owner: "actions", Name: "checkout"},
+				{Owner: "tj-actions", Name: "changed-files"},
+			},
+		},
+		{
+			name: "no repositories if the repository rules are disabled",
+			opts: []WorkflowLintOption{
+				WithEnforceVerifiedOrganization(true),
+				WithDisabledRules([]string{RuleIDArchivedAction, RuleIDInactiveRepo}),
+			},
+			want: []prefetchNode{
+				{Owner: "tj-actions"},
+			},
+		},
+		{
+			name: "nothing if the network rules are disabled",
+			opts: []WorkflowLintOption{
+				WithEnforceVerifiedOrganization(true),
+				WithDisabledRules([]string{RuleIDVerifiedOrg, RuleIDArchivedAction, RuleIDInactiveRepo}),
+			},
+			want: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			r := require.New(t)
+
+			params, err := NewWorkflowLintParams(tc.opts...)
+			r.NoError(err)
+
+			l := linter{logger: testLogger}
+			nodeSet := l.collectPrefetchNodes([]WorkflowLintInfo{
+				{
+					FilePath: "/path/to/repo/.github/workflows/ci.yml",
+					RepoID:   "owner/repo",
+					Content:  content,
+					Params:   params,
+				},
+			})
+
+			a.Equal(tc.want, nodeSet.hostNodes[""])
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package linter
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// Release represents a commit that a ref of an action points to.
+type Release struct {
+	// CommitHash is a commit hash that the ref resolves to.
+	CommitHash string
+
+	// CommittedAt is a committed date of the commit.
+	CommittedAt time.Time
+
+	// TaggedAt is a date of the annotated tag. It is nil for commit hashes and lightweight tags.
+	TaggedAt *time.Time
+
+	// PublishedAt is a date that the GitHub release of the tag was published. It is nil if the ref has no release.
+	PublishedAt *time.Time
+}
+
+// ReleasedAt returns the date that the ref was published: the latest of the committed date, the tagged date,
+// and the published date of the GitHub release.
+//
+// The committed date and the tagged date are recorded by the publisher and can be backdated,
+// while the published date of a release is recorded by GitHub.
+func (r Release) ReleasedAt() time.Time {
+	releasedAt := r.CommittedAt
+
+	for _, t := range []*time.Time{r.TaggedAt, r.PublishedAt} {
+		if t != nil && t.After(releasedAt) {
+			releasedAt = *t
+		}
+	}
+
+	return releasedAt
+}
+
+// Age returns the elapsed time since the release.
+func (r Release) Age(now time.Time) time.Duration {
+	return now.Sub(r.ReleasedAt())
+}
+
+// getRelease returns the commit that a ref (a tag, a branch or a commit hash) of an action points to.
+func (l *linter) getRelease(a Action) (*Release, error) {
+	return l.lookups.releases.Do(refKey(a.Host, a.Owner, a.Name, a.Ref), func() (*Release, error) {
+		return l.fetchRelease(a)
+	})
+}
+
+func (l *linter) fetchRelease(a Action) (*Release, error) {
+	type commit struct {
+		Oid           string
+		CommittedDate time.Time
+	}
+	var queryRelease struct {
+		Repository struct {
+			Object *struct {
+				Commit commit `graphql:"... on Commit"`
+				Tag    struct {
+					Tagger *struct {
+						Date time.Time
+					}
+					Target struct {
+						Commit commit `graphql:"... on Commit"`
+					}
+				} `graphql:"... on Tag"`
+			} `graphql:"object(expression: $expression)"`
+			Release *struct {
+				PublishedAt *time.Time
+			} `graphql:"release(tagName: $expression)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":      githubv4.String(a.Owner),
+		"name":       githubv4.String(a.Name),
+		"expression": githubv4.String(a.Ref),
+	}
+	queryParams, err := l.getQueryParams(a.Host, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query(&queryRelease, queryParams); err != nil {
+		return nil, err
+	}
+
+	obj := queryRelease.Repository.Object
+	if obj == nil {
+		return nil, fmt.Errorf("ref not found: repo=%s, ref=%s", a.RepoID(), a.Ref)
+	}
+
+	var release *Release
+	switch {
+	case obj.Commit.Oid != "":
+		release = &Release{
+			CommitHash:  obj.Commit.Oid,
+			CommittedAt: obj.Commit.CommittedDate,
+		}
+	case obj.Tag.Target.Commit.Oid != "":
+		release = &Release{
+			CommitHash:  obj.Tag.Target.Commit.Oid,
+			CommittedAt: obj.Tag.Target.Commit.CommittedDate,
+		}
+		if obj.Tag.Tagger != nil {
+			release.TaggedAt = &obj.Tag.Tagger.Date
+		}
+	default:
+		return nil, fmt.Errorf("ref does not point to a commit: repo=%s, ref=%s", a.RepoID(), a.Ref)
+	}
+
+	// the release of a tag is found regardless of whether the tag is annotated. commit hashes have no release.
+	if r := queryRelease.Repository.Release; r != nil {
+		release.PublishedAt = r.PublishedAt
+	}
+
+	return release, nil
+}
+
+// isReleaseAgeAllowlisted returns true if the commit hash that the action refers to is in the release age allowlist.
+func isReleaseAgeAllowlisted(action Action, commitHash string, params *WorkflowLintParams) (bool, *AllowedEntry) {
+	for _, entry := range params.GetReleaseAgeAllowlist(action) {
+		if entry.SHA == commitHash {
+			return true, &entry
+		}
+	}
+
+	return false, nil
+}
+
+// checkReleaseAge checks if the ref of an action is older than the minimum release age.
+// Recently published refs are rejected to give the community time to detect compromised releases.
+func (l *linter) checkReleaseAge(action Action, wfLintInfo WorkflowLintInfo) *Error {
+	params := wfLintInfo.Params
+	if params.MinReleaseAge == nil || *params.MinReleaseAge <= 0 {
+		return nil
+	}
+
+	release, err := l.getRelease(action)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to get the release date: %s", err.Error()),
+			wfLintInfo, action.Pos, KindRuntimeError)
+	}
+
+	minAge := time.Duration(*params.MinReleaseAge) * 24 * time.Hour
+	age := release.Age(time.Now())
+	if age >= minAge {
+		return nil
+	}
+
+	if allowlisted, entry := isReleaseAgeAllowlisted(action, release.CommitHash, params); allowlisted {
+		var comment string
+		if entry.Comment != nil {
+			comment = *entry.Comment
+		}
+
+		l.logger.Warn("too new release is allowlisted",
+			slog.String("action", action.String()),
+			slog.String("released-at", release.ReleasedAt().Format(time.RFC3339)),
+			slog.String("comment", comment),
+		)
+		return nil
+	}
+
+	return NewError(
+		fmt.Sprintf("too new release: action=%s, ref=%s, released-at=%s, min-release-age=%dd",
+			action.RepoID(), shortenHash(action.Ref), release.ReleasedAt().Format("2006-01-02"), *params.MinReleaseAge),
+		wfLintInfo, action.Pos, KindTooNewRelease)
+}
@@ -0,0 +1,121 @@
+package linter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelease_ReleasedAt(t *testing.T) {
+	a := assert.New(t)
+
+	committedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	taggedBefore := committedAt.Add(-time.Hour)
+	taggedAfter := committedAt.AddDate(0, 0, 10)
+	now := committedAt.AddDate(0, 0, 30)
+
+	testCases := []struct {
+		name    string
+		release Release
+		want    time.Time
+		wantAge time.Duration
+	}{
+		{
+			name:    "commit",
+			release: Release{CommittedAt: committedAt},
+			want:    committedAt,
+			wantAge: 30 * 24 * time.Hour,
+		},
+		{
+			name:    "tagged after the commit",
+			release: Release{CommittedAt: committedAt, TaggedAt: &taggedAfter},
+			want:    taggedAfter,
+			wantAge: 20 * 24 * time.Hour,
+		},
+		{
+			name:    "published after the tag",
+			release: Release{CommittedAt: committedAt, TaggedAt: &taggedBefore, PublishedAt: &taggedAfter},
+			want:    taggedAfter,
+			wantAge: 20 * 24 * time.Hour,
+		},
+		{
+			name:    "tagged before the commit",
+			release: Release{CommittedAt: committedAt, TaggedAt: &taggedBefore},
+			want:    committedAt,
+			wantAge: 30 * 24 * time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		a.Equal(tc.want, tc.release.ReleasedAt(), tc.name)
+		a.Equal(tc.wantAge, tc.release.Age(now), tc.name)
+	}
+}
+
+func TestIsReleaseAgeAllowlisted(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	const sha = "11bd71901bbe5b1630ceea73d27597364c9af683"
+
+	params, err := NewWorkflowLintParams(
+		WithReleaseAgeAllowlist(map[string][]AllowedEntry{
+			"actions/checkout": {{SHA: sha}},
+		}),
+	)
+	r.NoError(err)
+
+	testCases := []struct {
+		name       string
+		action     Action
+		commitHash string
+		want       bool
+	}{
+		{
+			name:       "pinned by allowlisted hash",
+			action:     Action{ID: "actions/checkout", Owner: "actions", Name: "checkout", Ref: sha},
+			commitHash: sha,
+			want:       true,
+		},
+		{
+			name:       "tag resolves to allowlisted hash",
+			action:     Action{ID: "actions/checkout", Owner: "actions", Name: "checkout", Ref: "v4"},
+			commitHash: sha,
+			want:       true,
+		},
+		{
+			name:       "not allowlisted hash",
+			action:     Action{ID: "actions/checkout", Owner: "actions", Name: "checkout", Ref: "v5"},
+			commitHash: "08c6903cd8c0fde910a37f88322edcfb5dd907a8",
+			want:       false,
+		},
+		{
+			name:       "not allowlisted action",
+			action:     Action{ID: "actions/setup-go", Owner: "actions", Name: "setup-go", Ref: sha},
+			commitHash: sha,
+			want:       false,
+		},
+	}
+
+	for _, tc := range testCases {
+		got, _ := isReleaseAgeAllowlisted(tc.action, tc.commitHash, params)
+		a.Equal(tc.want, got, tc.name)
+	}
+}
+
+func TestWithMinReleaseAge(t *testing.T) {
+	a := assert.New(t)
+
+	params, err := NewWorkflowLintParams()
+	a.NoError(err)
+	a.Equal(DefaultMinReleaseAge, *params.MinReleaseAge)
+
+	params, err = NewWorkflowLintParams(WithMinReleaseAge(7))
+	a.NoError(err)
+	a.Equal(7, *params.MinReleaseAge)
+
+	_, err = NewWorkflowLintParams(WithMinReleaseAge(-1))
+	a.Error(err)
+}
@@ -0,0 +1,190 @@
+package linter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rhysd/actionlint"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity represents a severity level of a finding.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNotice  Severity = "notice"
+)
+
+// ParseSeverity parses a severity string: error, warning, or notice.
+func ParseSeverity(s string) (Severity, error) {
+	switch severity := Severity(strings.ToLower(strings.TrimSpace(s))); severity {
+	case SeverityError, SeverityWarning, SeverityNotice:
+		return severity, nil
+	default:
+		return "", fmt.Errorf("invalid severity: expected=%v, actual=%s", []Severity{SeverityError, SeverityWarning, SeverityNotice}, s)
+	}
+}
+
+// UnmarshalYAML unmarshals a severity string in a config file.
+func (s *Severity) UnmarshalYAML(value *yaml.Node) error {
+	var str string
+	if err := value.Decode(&str); err != nil {
+		return err
+	}
+
+	severity, err := ParseSeverity(str)
+	if err != nil {
+		return err
+	}
+
+	*s = severity
+
+	return nil
+}
+
+// Rule is a check of an action that is referenced by a 'uses' value of a workflow.
+// Implement the interface and register it to a linter to add a custom rule.
+type Rule interface {
+	// ID returns a unique and stable ID of the rule: e.g. AA001
+	ID() string
+
+	// Description returns a short description of the rule.
+	Description() string
+
+	// DefaultSeverity returns a severity of the findings of the rule.
+	DefaultSeverity() Severity
+
+	// Check checks an action and returns findings. It returns nil if the action complies with the rule.
+	// Runtime errors are reported as findings of KindRuntimeError.
+	Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error
+}
+
+// NetworkRule is a rule that queries GitHub to check actions.
+// Rules that return true from RequiresNetwork are skipped in the offline mode.
+// Rules that do not implement the interface are considered to work offline.
+type NetworkRule interface {
+	Rule
+
+	// RequiresNetwork returns true if the rule requires network access.
+	RequiresNetwork() bool
+}
+
+// requiresNetwork returns true if the rule requires network access.
+func requiresNetwork(rule Rule) bool {
+	networkRule, ok := rule.(NetworkRule)
+
+	return ok && networkRule.RequiresNetwork()
+}
+
+// RuleRegistry is a set of rules. Rules are applied in the registered order.
+type RuleRegistry struct {
+	mu    sync.RWMutex
+	rules []Rule
+}
+
+// NewRuleRegistry creates a new RuleRegistry instance with rules.
+func NewRuleRegistry(rules ...Rule) (*RuleRegistry, error) {
+	r := &RuleRegistry{
+		rules: make([]Rule, 0, len(rules)),
+	}
+
+	for _, rule := range rules {
+		if err := r.Register(rule); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Register adds a rule to the registry. It returns an error if a rule with the same ID is already registered.
+func (r *RuleRegistry) Register(rule Rule) error {
+	if rule.ID() == "" {
+		return fmt.Errorf("require a rule ID")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.ContainsFunc(r.rules, func(registered Rule) bool {
+		return registered.ID() == rule.ID()
+	}) {
+		return fmt.Errorf("duplicate rule ID: %s", rule.ID())
+	}
+
+	r.rules = append(r.rules, rule)
+
+	return nil
+}
+
+// Lookup returns a rule of the ID.
+func (r *RuleRegistry) Lookup(id string) (Rule, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rule := range r.rules {
+		if rule.ID() == id {
+			return rule, true
+		}
+	}
+
+	return nil, false
+}
+
+// ValidateRuleConfigs returns an error if any of the rule configurations refers to a rule that is not registered.
+// This prevents typos of rule IDs from silently leaving the rules enabled.
+func (r *RuleRegistry) ValidateRuleConfigs(configs map[string]RuleConfig) error {
+	unknownIDs := make([]string, 0)
+
+	for id := range configs {
+		if _, exist := r.Lookup(id); !exist {
+			unknownIDs = append(unknownIDs, id)
+		}
+	}
+
+	if len(unknownIDs) == 0 {
+		return nil
+	}
+
+	slices.Sort(unknownIDs)
+
+	return fmt.Errorf("unknown rule IDs: %s", strings.Join(unknownIDs, ", "))
+}
+
+// Rules returns the registered rules in the registered order.
+func (r *RuleRegistry) Rules() []Rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.rules)
+}
+
+// RuleConfig represents a configuration of a rule.
+type RuleConfig struct {
+	// Enabled is a flag to enable the rule. Rules are enabled by default.
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// Severity overrides the default severity of the rule.
+	Severity *Severity `yaml:"severity,omitempty"`
+}
+
+// NewError creates a finding at a position of a workflow.
+// This is a helper for rules to report findings: pos is usually Action.Pos or Action.RefPos().
+// The severity of the finding is left empty so that the configured severity of the rule is applied.
+// Set the Severity field to report a finding with a specific severity.
+func NewError(msg string, wfLintInfo WorkflowLintInfo, pos *actionlint.Pos, kind ErrorKind) *Error {
+	relPath, err := wfLintInfo.RelPath()
+	if err != nil {
+		relPath = wfLintInfo.FilePath
+	}
+
+	lintError := newLintError(msg, relPath, wfLintInfo, pos, kind)
+	lintError.Severity = ""
+
+	return lintError
+}
@@ -0,0 +1,263 @@
+package linter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRule struct {
+	id string
+}
+
+func (r stubRule) ID() string {
+	return r.id
+}
+
+func (r stubRule) Description() string {
+	return "stub rule"
+}
+
+func (r stubRule) DefaultSeverity() Severity {
+	return SeverityNotice
+}
+
+func (r stubRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	return nil
+}
+
+func TestRuleRegistry(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	registry, err := NewRuleRegistry(stubRule{id: "X002"}, stubRule{id: "X001"})
+	r.NoError(err)
+
+	a.EqualError(registry.Register(stubRule{id: "X001"}), "duplicate rule ID: X001")
+	a.EqualError(registry.Register(stubRule{id: ""}), "require a rule ID")
+	a.NoError(registry.Register(stubRule{id: "X003"}))
+
+	ids := []string{}
+	for _, rule := range registry.Rules() {
+		ids = append(ids, rule.ID())
+	}
+	a.Equal([]string{"X002", "X001", "X003"}, ids)
+
+	rule, found := registry.Lookup("X001")
+	a.True(found)
+	a.Equal("X001", rule.ID())
+
+	_, found = registry.Lookup("X999")
+	a.False(found)
+
+	_, err = NewRuleRegistry(stubRule{id: "X001"}, stubRule{id: "X001"})
+	a.Error(err)
+}
+
+func TestRuleRegistry_ValidateRuleConfigs(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	registry, err := NewRuleRegistry(newBuiltinRules(&linter{})...)
+	r.NoError(err)
+	r.NoError(registry.Register(stubRule{id: "X001"}))
+
+	disabled := false
+
+	testCases := []struct {
+		name    string
+		configs map[string]RuleConfig
+		wantErr string
+	}{
+		{
+			name:    "no configurations",
+			configs: nil,
+		},
+		{
+			name: "built-in and custom rules",
+			configs: map[string]RuleConfig{
+				RuleIDInactiveRepo: {Enabled: &disabled},
+				"X001":             {Enabled: &disabled},
+			},
+		},
+		{
+			name: "unknown rules",
+			configs: map[string]RuleConfig{
+				RuleIDInactiveRepo: {Enabled: &disabled},
+				"AA01":             {Enabled: &disabled},
+				"X999":             {Enabled: &disabled},
+			},
+			wantErr: "unknown rule IDs: AA01, X999",
+		},
+	}
+
+	for _, tc := range testCases {
+		err := registry.ValidateRuleConfigs(tc.configs)
+		if tc.wantErr != "" {
+			a.EqualError(err, tc.wantErr, tc.name)
+			continue
+		}
+		a.NoError(err, tc.name)
+	}
+}
+
+func TestBuiltinRules(t *testing.T) {
+	a := assert.New(t)
+
+	_, err := NewRuleRegistry(newBuiltinRules(&linter{})...)
+	a.NoError(err)
+}
+
+func TestWorkflowLintParams_IsRuleEnabled(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	params, err := NewWorkflowLintParams(
+		WithRuleConfigs(map[string]RuleConfig{
+			RuleIDPinHash:      {Enabled: boolPtr(true)},
+			RuleIDInactiveRepo: {Enabled: boolPtr(true)},
+		}),
+		WithDisabledRules([]string{RuleIDInactiveRepo, RuleIDReleaseAge}),
+	)
+	r.NoError(err)
+
+	a.True(params.IsRuleEnabled(RuleIDPinHash))
+	a.True(params.IsRuleEnabled(RuleIDArchivedAction))
+	a.False(params.IsRuleEnabled(RuleIDInactiveRepo))
+	a.False(params.IsRuleEnabled(RuleIDReleaseAge))
+}
+
+func TestWorkflowLintParams_IsTrustedAction(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	const sha = "11bd71901bbe5b1630ceea73d27597364c9af683"
+
+	params, err := NewWorkflowLintParams(
+		WithExcludeOfficialActions(true),
+		WithCreatorAllowlist([]string{"google-github-actions"}),
+		WithActionAllowlist([]string{"docker/login-action"}),
+		WithHashAllowlist(map[string][]AllowedEntry{
+			"goreleaser/goreleaser-action": {{SHA: sha}},
+		}),
+	)
+	r.NoError(err)
+
+	testCases := []struct {
+		name       string
+		action     Action
+		want       bool
+		wantReason string
+	}{
+		{
+			name:       "official",
+			action:     Action{ID: "actions/checkout", Owner: "actions", Name: "checkout", Ref: "v4"},
+			want:       true,
+			wantReason: "official action",
+		},
+		{
+			name:       "allowlisted creator",
+			action:     Action{ID: "google-github-actions/auth", Owner: "google-github-actions", Name: "auth", Ref: "v2"},
+			want:       true,
+			wantReason: "allowlisted creator",
+		},
+		{
+			name:       "allowlisted action",
+			action:     Action{ID: "docker/login-action", Owner: "docker", Name: "login-action", Ref: "v3"},
+			want:       true,
+			wantReason: "allowlisted action",
+		},
+		{
+			name:       "allowlisted hash",
+			action:     Action{ID: "goreleaser/goreleaser-action", Owner: "goreleaser", Name: "goreleaser-action", Ref: sha},
+			want:       true,
+			wantReason: "pinned by allowlisted hash",
+		},
+		{
+			name:   "tag of allowlisted hash action",
+			action: Action{ID: "goreleaser/goreleaser-action", Owner: "goreleaser", Name: "goreleaser-action", Ref: "v6"},
+			want:   false,
+		},
+		{
+			name:   "not trusted",
+			action: Action{ID: "docker/build-push-action", Owner: "docker", Name: "build-push-action", Ref: "v6"},
+			want:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		got, reason := params.IsTrustedAction(tc.action)
+		a.Equal(tc.want, got, tc.name)
+		if tc.want {
+			a.Equal(tc.wantReason, reason, tc.name)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		value   string
+		want    Severity
+		wantErr bool
+	}{
+		{value: "error", want: SeverityError},
+		{value: "Warning", want: SeverityWarning},
+		{value: " notice ", want: SeverityNotice},
+		{value: "fatal", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseSeverity(tc.value)
+		if tc.wantErr {
+			a.Error(err, tc.value)
+			continue
+		}
+
+		a.NoError(err, tc.value)
+		a.Equal(tc.want, got, tc.value)
+	}
+}
+
+func TestWorkflowLintParams_GetRuleSeverity(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	params, err := toLintParams([]byte(`
+rules:
+    AA001:
+        severity: warning
+    AA005:
+        enabled: false
+`))
+	r.NoError(err)
+
+	a.Equal(SeverityWarning, params.GetRuleSeverity(stubRule{id: "AA001"}))
+	a.Equal(SeverityNotice, params.GetRuleSeverity(stubRule{id: "AA005"}))
+	a.False(params.IsRuleEnabled("AA005"))
+
+	_, err = toLintParams([]byte(`
+rules:
+    AA001:
+        severity: fatal
+`))
+	a.Error(err)
+}
+
+func TestError_DisplayKind(t *testing.T) {
+	a := assert.New(t)
+
+	lintError := newLintError("msg", "ci.yml", WorkflowLintInfo{}, nil, KindUnpinned)
+	a.Equal(SeverityError, lintError.Severity)
+	a.Equal("error: must be pinned by hash", lintError.DisplayKind())
+	a.Equal("must be pinned by hash", lintError.Title())
+
+	lintError.RuleID = RuleIDPinHash
+	lintError.Severity = SeverityWarning
+	a.Equal("warning AA001: must be pinned by hash", lintError.DisplayKind())
+	a.Equal("AA001", lintError.Title())
+}
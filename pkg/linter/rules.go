@@ -0,0 +1,296 @@
+package linter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// IDs of the built-in rules.
+const (
+	RuleIDPinHash           = "AA001"
+	RuleIDHashAllowlist     = "AA002"
+	RuleIDVerifiedOrg       = "AA003"
+	RuleIDArchivedAction    = "AA004"
+	RuleIDInactiveRepo      = "AA005"
+	RuleIDTagMoved          = "AA006"
+	RuleIDSignedCommit      = "AA007"
+	RuleIDReleaseAge        = "AA008"
+	RuleIDDeprecatedRuntime = "AA009"
+	RuleIDVulnerableAction  = "AA010"
+)
+
+// builtinRule is a base of the built-in rules.
+type builtinRule struct {
+	l               *linter
+	id              string
+	description     string
+	defaultSeverity Severity
+
+	// network is true if the rule cannot check actions without querying GitHub
+	network bool
+}
+
+func (r builtinRule) ID() string {
+	return r.id
+}
+
+func (r builtinRule) Description() string {
+	return r.description
+}
+
+func (r builtinRule) DefaultSeverity() Severity {
+	return r.defaultSeverity
+}
+
+func (r builtinRule) RequiresNetwork() bool {
+	return r.network
+}
+
+func toErrors(lintError *Error) []*Error {
+	if lintError == nil {
+		return nil
+	}
+
+	return []*Error{lintError}
+}
+
+func newBuiltinRules(l *linter) []Rule {
+	return []Rule{
+		&pinHashRule{builtinRule{l, RuleIDPinHash, "actions must be pinned by a commit hash", SeverityError, false}},
+		&hashAllowlistRule{builtinRule{l, RuleIDHashAllowlist, "pinned commit hashes must be in the hash allowlist", SeverityError, true}},
+		&verifiedOrgRule{builtinRule{l, RuleIDVerifiedOrg, "actions must be owned by verified organizations", SeverityError, true}},
+		&archivedActionRule{builtinRule{l, RuleIDArchivedAction, "actions must not be in archived repositories", SeverityError, true}},
+		&inactiveRepoRule{builtinRule{l, RuleIDInactiveRepo, "actions should be in actively maintained repositories", SeverityWarning, true}},
+		&tagMovedRule{builtinRule{l, RuleIDTagMoved, "tags must resolve to the commits recorded in the lockfile", SeverityError, true}},
+		&signedCommitRule{builtinRule{l, RuleIDSignedCommit, "pinned commits must be signed by expected signers", SeverityError, true}},
+		&releaseAgeRule{builtinRule{l, RuleIDReleaseAge, "referenced releases must be older than the minimum release age", SeverityError, true}},
+		&deprecatedRuntimeRule{builtinRule{l, RuleIDDeprecatedRuntime, "actions must not run on deprecated runtimes", SeverityError, true}},
+		&vulnerableActionRule{builtinRule{l, RuleIDVulnerableAction, "actions must not be affected by known advisories", SeverityError, false}},
+	}
+}
+
+type pinHashRule struct {
+	builtinRule
+}
+
+func (r pinHashRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	params := wfLintInfo.Params
+
+	if trusted, _ := params.IsTrustedAction(action); trusted || action.IsPinnedBySHA() {
+		return nil
+	}
+
+	lintError := NewError(
+		fmt.Sprintf("invalid ref value: action=%s, expected=SHA, actual=%s", action.RepoID(), action.Ref),
+		wfLintInfo, action.RefPos(), KindUnpinned,
+	)
+	if !*params.EnforcePinHash {
+		lintError.Severity = SeverityWarning
+	}
+
+	return toErrors(lintError)
+}
+
+type hashAllowlistRule struct {
+	builtinRule
+}
+
+func (r hashAllowlistRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	params := wfLintInfo.Params
+
+	if !*params.AllowOnlyAllowlistedHash || !action.IsPinnedBySHA() {
+		return nil
+	}
+
+	if trusted, _ := params.IsTrustedAction(action); trusted {
+		return nil
+	}
+
+	tagNames, err := r.l.resolveGitTagNamesFromSha(ctx, action.Repository(), action.Ref)
+	if err != nil {
+		return toErrors(NewError(
+			fmt.Sprintf("failed to resolve git tags: %s", err.Error()),
+			wfLintInfo, action.RefPos(), KindRuntimeError))
+	}
+	tagsStr := strings.Join(tagNames, ", ")
+	refShortHash := shortenHash(action.Ref)
+
+	allowedEntries := params.GetHashAllowlist(action)
+	allowlist := make([]string, 0, len(allowedEntries))
+
+	for _, entry := range allowedEntries {
+		var comment string
+		if entry.Comment != nil {
+			comment = strings.TrimSpace(replaceNewlines(*entry.Comment))
+		}
+
+		entryTagNames, err := r.l.resolveGitTagNamesFromSha(ctx, action.Repository(), entry.SHA)
+		if err != nil {
+			return toErrors(NewError(
+				fmt.Sprintf("failed to resolve git tags: %s", err.Error()),
+				wfLintInfo, action.RefPos(), KindRuntimeError))
+		}
+		entryTagsStr := strings.Join(entryTagNames, ", ")
+		entryShortSHA := shortenHash(entry.SHA)
+
+		var allowlistEntry string
+		if comment == "" {
+			allowlistEntry = fmt.Sprintf("%s(%s)", entryShortSHA, entryTagsStr)
+		} else {
+			allowlistEntry = fmt.Sprintf("%s(%s: %s)", entryShortSHA, entryTagsStr, truncateString(comment, maxCommentLen))
+		}
+
+		allowlist = append(allowlist, allowlistEntry)
+	}
+
+	return toErrors(NewError(
+		fmt.Sprintf("invalid ref value: action=%s, sha=%s(%s), allowlist=%v", action.ID, refShortHash, tagsStr, allowlist),
+		wfLintInfo, action.RefPos(), KindNotAllowlistedHash,
+	))
+}
+
+type verifiedOrgRule struct {
+	builtinRule
+}
+
+func (r verifiedOrgRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	params := wfLintInfo.Params
+
+	if params.EnforceVerifiedOrganization == nil || !*params.EnforceVerifiedOrganization {
+		return nil
+	}
+
+	if trusted, _ := params.IsTrustedAction(action); trusted {
+		return nil
+	}
+
+	verified, err := r.l.isVerifiedOrganization(action)
+	if err != nil {
+		return toErrors(NewError(
+			fmt.Sprintf("failed to check if the owner is verified: %s", err.Error()),
+			wfLintInfo, action.Pos, KindRuntimeError))
+	}
+
+	if !verified {
+		return toErrors(NewError(
+			fmt.Sprintf("organization is not verified: owner=%s", action.Owner),
+			wfLintInfo, action.Pos, KindUnverifiedOrganization))
+	}
+
+	return nil
+}
+
+type archivedActionRule struct {
+	builtinRule
+}
+
+func (r archivedActionRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	archived, archivedAt, err := r.l.isArchivedAction(action)
+	if err != nil {
+		return toErrors(NewError(
+			fmt.Sprintf("failed to check if the action is archived: %s", err.Error()),
+			wfLintInfo, action.Pos, KindRuntimeError))
+	}
+
+	if !archived {
+		return nil
+	}
+
+	lintError := NewError(
+		fmt.Sprintf("archived action found: repo=%s, archived-at=%s", action.RepoID(), archivedAt.Format("2006-01-02")),
+		wfLintInfo, action.Pos, KindArchivedActionUsed)
+	if *wfLintInfo.Params.AllowArchivedRepo {
+		lintError.Severity = SeverityWarning
+	}
+
+	return toErrors(lintError)
+}
+
+type inactiveRepoRule struct {
+	builtinRule
+}
+
+func (r inactiveRepoRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	params := wfLintInfo.Params
+
+	inactive, lastActiveAt, err := r.l.isInactiveAction(action, params)
+	if err != nil {
+		return toErrors(NewError(
+			fmt.Sprintf("failed to check the repository activity: %s", err.Error()),
+			wfLintInfo, action.Pos, KindRuntimeError))
+	}
+
+	if !inactive {
+		return nil
+	}
+
+	lastActiveAtStr := "unknown"
+	if lastActiveAt != nil {
+		lastActiveAtStr = lastActiveAt.Format("2006-01-02")
+	}
+
+	return toErrors(NewError(
+		fmt.Sprintf("inactive action found: repo=%s, last-active-at=%s, max-repo-inactivity=%d days", action.RepoID(), lastActiveAtStr, *params.MaxRepoInactivity),
+		wfLintInfo, action.Pos, KindInactiveRepo))
+}
+
+type tagMovedRule struct {
+	builtinRule
+}
+
+func (r tagMovedRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	return toErrors(r.l.checkLockedTag(ctx, action, wfLintInfo))
+}
+
+type signedCommitRule struct {
+	builtinRule
+}
+
+func (r signedCommitRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	params := wfLintInfo.Params
+
+	if !action.IsPinnedBySHA() {
+		return nil
+	}
+
+	if trusted, _ := params.IsTrustedAction(action); trusted {
+		return nil
+	}
+
+	msg, kind, err := r.l.checkCommitSignature(action, params)
+	if err != nil {
+		return toErrors(NewError(
+			fmt.Sprintf("failed to check the commit signature: %s", err.Error()),
+			wfLintInfo, action.RefPos(), KindRuntimeError))
+	}
+	if msg != "" {
+		return toErrors(NewError(msg, wfLintInfo, action.RefPos(), kind))
+	}
+
+	return nil
+}
+
+type releaseAgeRule struct {
+	builtinRule
+}
+
+func (r releaseAgeRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	return toErrors(r.l.checkReleaseAge(action, wfLintInfo))
+}
+
+type deprecatedRuntimeRule struct {
+	builtinRule
+}
+
+func (r deprecatedRuntimeRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	return toErrors(r.l.checkDeprecatedRuntime(ctx, action, wfLintInfo))
+}
+
+type vulnerableActionRule struct {
+	builtinRule
+}
+
+func (r vulnerableActionRule) Check(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) []*Error {
+	return toErrors(r.l.checkAdvisories(ctx, action, wfLintInfo))
+}
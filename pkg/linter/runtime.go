@@ -0,0 +1,281 @@
+package linter
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/thombashi/gh-actionarmor/pkg/workflow"
+	gitdescribe "github.com/thombashi/gh-git-describe/pkg/executor"
+)
+
+// GitHubDeprecatedRuntimes is a list of runtimes (runs.using) that GitHub Actions deprecated.
+// The deprecated runtime check is opt-in: set the list to WorkflowLintParams.DeprecatedRuntimes to enable the check.
+var GitHubDeprecatedRuntimes = []string{"node12", "node16"}
+
+var reVersionTag = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)$`)
+
+// parseVersionTag parses a version tag (e.g. v4, v4.1.0) into numeric components.
+// It returns false if the tag is not a version tag. Pre-release tags are not regarded as version tags.
+func parseVersionTag(tag string) ([]int, bool) {
+	matches := reVersionTag.FindStringSubmatch(tag)
+	if matches == nil {
+		return nil, false
+	}
+
+	items := strings.Split(matches[1], ".")
+	version := make([]int, 0, len(items))
+	for _, item := range items {
+		v, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, false
+		}
+
+		version = append(version, v)
+	}
+
+	return version, true
+}
+
+// sortVersionTags returns version tags in ascending order. Tags that are not version tags are excluded.
+// A tag with fewer components comes first if the common components are the same (e.g. v4 < v4.0.0).
+func sortVersionTags(tags []string) []string {
+	type versionTag struct {
+		tag     string
+		version []int
+	}
+
+	versionTags := make([]versionTag, 0, len(tags))
+	for _, tag := range tags {
+		if version, ok := parseVersionTag(tag); ok {
+			versionTags = append(versionTags, versionTag{tag: tag, version: version})
+		}
+	}
+
+	slices.SortStableFunc(versionTags, func(a, b versionTag) int {
+		return slices.Compare(a.version, b.version)
+	})
+
+	sorted := make([]string, 0, len(versionTags))
+	for _, vt := range versionTags {
+		sorted = append(sorted, vt.tag)
+	}
+
+	return sorted
+}
+
+// newerVersionTags returns version tags that are newer than the current version in ascending order.
+// Versions are compared by their common components: a floating tag (e.g. v4) is regarded as the same version as v4.x.y.
+func newerVersionTags(tags []string, current []int) []string {
+	newer := make([]string, 0)
+
+	for _, tag := range sortVersionTags(tags) {
+		version, _ := parseVersionTag(tag)
+		n := min(len(version), len(current))
+		if slices.Compare(version[:n], current[:n]) > 0 {
+			newer = append(newer, tag)
+		}
+	}
+
+	return newer
+}
+
+// majorVersionCandidates returns a version tag per major version of the tags newer than the current version
+// in ascending order: the floating tag of the major version (e.g. v5) if it exists, or the newest tag of the major version.
+func majorVersionCandidates(tags []string, current []int) []string {
+	candidates := make([]string, 0)
+	candidateMajors := make([]int, 0)
+
+	for _, tag := range newerVersionTags(tags, current) {
+		version, _ := parseVersionTag(tag)
+		major := version[0]
+
+		if i := len(candidates) - 1; i >= 0 && candidateMajors[i] == major {
+			// the floating tag comes first in the tags of a major version
+			if prev, _ := parseVersionTag(candidates[i]); len(prev) > 1 {
+				candidates[i] = tag
+			}
+			continue
+		}
+
+		candidates = append(candidates, tag)
+		candidateMajors = append(candidateMajors, major)
+	}
+
+	return candidates
+}
+
+// gitRepo is a remote repository that is read through the repository cache of a git-describe executor.
+type gitRepo struct {
+	executor gitdescribe.Executor
+	params   *gitdescribe.RepoCloneParams
+}
+
+func (r gitRepo) runGit(ctx context.Context, command string, args ...string) (string, error) {
+	return r.executor.RunGitContext(ctx, r.params, command, args...)
+}
+
+// readFile reads a file of the repository at a revision. name is a slash-separated path from the repository root.
+func (r gitRepo) readFile(ctx context.Context, rev, name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+
+	// the executor does not expose exit codes. check the existence of the file before reading it.
+	found, err := r.runGit(ctx, "ls-tree", "--name-only", rev, "--", name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	if found == "" {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	content, err := r.runGit(ctx, "cat-file", "blob", fmt.Sprintf("%s:%s", rev, name))
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+
+	return []byte(content), nil
+}
+
+func (l *linter) getGitDescribeExecutor(host string) (gitdescribe.Executor, error) {
+	clients, err := l.getClients(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if clients.GdExecutor == nil {
+		return nil, fmt.Errorf("git-describe executor is not available: host=%s", host)
+	}
+
+	return clients.GdExecutor, nil
+}
+
+func (l *linter) newGitRepo(action Action) (*gitRepo, error) {
+	executor, err := l.getGitDescribeExecutor(action.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitRepo{
+		executor: executor,
+		params:   &gitdescribe.RepoCloneParams{RepoID: action.FullRepoID()},
+	}, nil
+}
+
+// readActionMetadata reads the metadata file (action.yml) of an action at a revision.
+func (l *linter) readActionMetadata(ctx context.Context, action Action, rev string) (*workflow.ActionMetadata, error) {
+	key := refKey(action.Host, action.Owner, action.Name, rev) + ":" + action.SubPath()
+
+	return l.lookups.metadata.DoContext(ctx, key, func(ctx context.Context) (*workflow.ActionMetadata, error) {
+		repo, err := l.newGitRepo(action)
+		if err != nil {
+			return nil, err
+		}
+
+		return workflow.ReadActionMetadataFunc(func(name string) ([]byte, error) {
+			return repo.readFile(ctx, rev, name)
+		}, action.SubPath())
+	})
+}
+
+// listTags returns tags of an action repository.
+func (l *linter) listTags(ctx context.Context, action Action) ([]string, error) {
+	return l.lookups.tags.DoContext(ctx, repoKey(action.Host, action.Owner, action.Name), func(ctx context.Context) ([]string, error) {
+		repo, err := l.newGitRepo(action)
+		if err != nil {
+			return nil, err
+		}
+
+		stdout, err := repo.runGit(ctx, "tag", "--list")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: repo=%s, error=%w", action.RepoID(), err)
+		}
+
+		return strings.Fields(stdout), nil
+	})
+}
+
+// currentVersion returns the version of the ref of an action.
+// For a commit hash, it returns the newest version tag that points to the commit.
+func (l *linter) currentVersion(ctx context.Context, action Action) ([]int, bool) {
+	if !action.IsPinnedBySHA() {
+		return parseVersionTag(action.Ref)
+	}
+
+	tagNames, err := l.resolveGitTagNamesFromSha(ctx, action.Repository(), action.Ref)
+	if err != nil {
+		l.logger.Debug("failed to resolve git tags", slog.String("action", action.String()), slog.Any("error", err))
+		return nil, false
+	}
+
+	sorted := sortVersionTags(tagNames)
+	if len(sorted) == 0 {
+		return nil, false
+	}
+
+	return parseVersionTag(sorted[len(sorted)-1])
+}
+
+// suggestSupportedVersion returns a version tag newer than the ref of an action that uses a supported runtime.
+// A tag per major version is checked in ascending order, and the first one that uses a supported runtime is returned.
+// It returns an empty string if no such tag is found.
+func (l *linter) suggestSupportedVersion(ctx context.Context, action Action, deprecatedRuntimes []string) string {
+	current, ok := l.currentVersion(ctx, action)
+	if !ok {
+		return ""
+	}
+
+	tags, err := l.listTags(ctx, action)
+	if err != nil {
+		l.logger.Debug("failed to list tags", slog.String("action", action.String()), slog.Any("error", err))
+		return ""
+	}
+
+	for _, tag := range majorVersionCandidates(tags, current) {
+		metadata, err := l.readActionMetadata(ctx, action, tag)
+		if err != nil {
+			l.logger.Debug("failed to read the action metadata",
+				slog.String("action", action.RepoID()), slog.String("tag", tag), slog.Any("error", err))
+			continue
+		}
+
+		if metadata.Runs.Using != "" && !slices.Contains(deprecatedRuntimes, metadata.Runs.Using) {
+			return tag
+		}
+	}
+
+	return ""
+}
+
+// checkDeprecatedRuntime checks if an action runs on a deprecated runtime at the ref.
+func (l *linter) checkDeprecatedRuntime(ctx context.Context, action Action, wfLintInfo WorkflowLintInfo) *Error {
+	deprecatedRuntimes := wfLintInfo.Params.DeprecatedRuntimes
+	if len(deprecatedRuntimes) == 0 || action.IsReusableWorkflow() {
+		return nil
+	}
+
+	metadata, err := l.readActionMetadata(ctx, action, action.Ref)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to read the action metadata: %s", err.Error()),
+			wfLintInfo, action.Pos, KindRuntimeError)
+	}
+
+	if !slices.Contains(deprecatedRuntimes, metadata.Runs.Using) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("deprecated runtime found: action=%s, ref=%s, using=%s",
+		action.ID, shortenHash(action.Ref), metadata.Runs.Using)
+	if suggestion := l.suggestSupportedVersion(ctx, action, deprecatedRuntimes); suggestion != "" {
+		msg += fmt.Sprintf(", suggestion=%s", suggestion)
+	}
+
+	return NewError(msg, wfLintInfo, action.Pos, KindDeprecatedRuntime)
+}
@@ -0,0 +1,58 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersionTag(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		tag    string
+		want   []int
+		wantOK bool
+	}{
+		{tag: "v4", want: []int{4}, wantOK: true},
+		{tag: "v4.1.7", want: []int{4, 1, 7}, wantOK: true},
+		{tag: "1.2", want: []int{1, 2}, wantOK: true},
+		{tag: "v4.0.0-beta", want: nil, wantOK: false},
+		{tag: "main", want: nil, wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		got, ok := parseVersionTag(tc.tag)
+		a.Equal(tc.wantOK, ok, tc.tag)
+		a.Equal(tc.want, got, tc.tag)
+	}
+}
+
+func TestNewerVersionTags(t *testing.T) {
+	a := assert.New(t)
+
+	tags := []string{"v4.0.0", "v3", "v10.0.0", "v3.6.0", "v4", "latest", "v3.5.3", "v4.1.0-rc1", "v4.1.0"}
+
+	a.Equal([]string{"v3", "v3.5.3", "v3.6.0", "v4", "v4.0.0", "v4.1.0", "v10.0.0"}, sortVersionTags(tags))
+	a.Equal([]string{"v4", "v4.0.0", "v4.1.0", "v10.0.0"}, newerVersionTags(tags, []int{3, 6, 0}))
+	a.Empty(newerVersionTags(tags, []int{10}))
+}
+
+func TestMajorVersionCandidates(t *testing.T) {
+	a := assert.New(t)
+
+	tags := []string{"v4.0.0", "v3", "v10.0.0", "v10.1.0", "v3.6.0", "v4", "latest", "v3.5.3", "v4.1.0-rc1", "v4.1.0"}
+
+	// floating tags are preferred, and the newest tags are used for major versions without a floating tag
+	a.Equal([]string{"v4", "v10.1.0"}, majorVersionCandidates(tags, []int{3, 6, 0}))
+	a.Equal([]string{"v4.1.0", "v10.1.0"}, majorVersionCandidates(tags, []int{4, 0, 0}))
+	a.Empty(majorVersionCandidates(tags, []int{10, 1, 0}))
+}
+
+func TestAction_IsReusableWorkflow(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(Action{ID: "octo-org/example-repo/.github/workflows/reusable.yml", Owner: "octo-org", Name: "example-repo"}.IsReusableWorkflow())
+	a.False(Action{ID: "actions/checkout", Owner: "actions", Name: "checkout"}.IsReusableWorkflow())
+	a.False(Action{ID: "github/codeql-action/init", Owner: "github", Name: "codeql-action"}.IsReusableWorkflow())
+}
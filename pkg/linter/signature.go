@@ -0,0 +1,180 @@
+package linter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// webFlowSigner is an identity of commits that were signed by GitHub (e.g. commits created on the web interface).
+const webFlowSigner = "web-flow"
+
+// CommitSignature represents a signature of a commit.
+type CommitSignature struct {
+	// IsValid is true if the signature is valid and verified by GitHub.
+	IsValid bool
+
+	// State is a state of the signature: e.g. VALID, UNKNOWN_KEY, BAD_EMAIL
+	State string
+
+	// Signer is a login of the user who signed the commit. It is empty if the signer is not a GitHub user.
+	Signer string
+
+	// Email is an email address associated with the signature.
+	Email string
+
+	// KeyID is a GPG key ID or an SSH key fingerprint of the signature.
+	KeyID string
+
+	// WasSignedByGitHub is true if the commit was signed by GitHub.
+	WasSignedByGitHub bool
+}
+
+// Identities returns identities of the signer: login, email, key ID, and 'web-flow' if the commit was signed by GitHub.
+func (s CommitSignature) Identities() []string {
+	identities := make([]string, 0, 4)
+
+	for _, identity := range []string{s.Signer, s.Email, s.KeyID} {
+		if identity != "" {
+			identities = append(identities, identity)
+		}
+	}
+
+	if s.WasSignedByGitHub {
+		identities = append(identities, webFlowSigner)
+	}
+
+	return identities
+}
+
+// MatchesAny returns true if any of the identities of the signer matches one of the expected signers.
+// The comparison is case-insensitive.
+func (s CommitSignature) MatchesAny(expectedSigners []string) bool {
+	for _, identity := range s.Identities() {
+		if slices.ContainsFunc(expectedSigners, func(expected string) bool {
+			return strings.EqualFold(identity, strings.TrimSpace(expected))
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s CommitSignature) String() string {
+	identities := s.Identities()
+	if len(identities) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(identities, ", ")
+}
+
+// getCommitSignature returns a signature of the commit that the action is pinned to.
+// It returns nil if the commit is not signed.
+func (l *linter) getCommitSignature(a Action) (*CommitSignature, error) {
+	return l.lookups.signatures.Do(refKey(a.Host, a.Owner, a.Name, a.Ref), func() (*CommitSignature, error) {
+		return l.fetchCommitSignature(a)
+	})
+}
+
+func (l *linter) fetchCommitSignature(a Action) (*CommitSignature, error) {
+	var queryCommitSignature struct {
+		Repository struct {
+			Object *struct {
+				Commit struct {
+					Signature *struct {
+						IsValid           bool
+						State             string
+						Email             string
+						WasSignedByGitHub bool
+						Signer            *struct {
+							Login string
+						}
+						GpgSignature struct {
+							KeyID string `graphql:"keyId"`
+						} `graphql:"... on GpgSignature"`
+						SSHSignature struct {
+							KeyFingerprint string
+						} `graphql:"... on SshSignature"`
+					}
+				} `graphql:"... on Commit"`
+			} `graphql:"object(oid: $oid)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner": githubv4.String(a.Owner),
+		"name":  githubv4.String(a.Name),
+		"oid":   githubv4.GitObjectID(a.Ref),
+	}
+	queryParams, err := l.getQueryParams(a.Host, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query(&queryCommitSignature, queryParams); err != nil {
+		return nil, err
+	}
+
+	obj := queryCommitSignature.Repository.Object
+	if obj == nil {
+		return nil, fmt.Errorf("commit not found: repo=%s, sha=%s", a.RepoID(), a.Ref)
+	}
+
+	signature := obj.Commit.Signature
+	if signature == nil {
+		return nil, nil
+	}
+
+	commitSignature := &CommitSignature{
+		IsValid:           signature.IsValid,
+		State:             signature.State,
+		Email:             signature.Email,
+		KeyID:             signature.GpgSignature.KeyID,
+		WasSignedByGitHub: signature.WasSignedByGitHub,
+	}
+	if commitSignature.KeyID == "" {
+		commitSignature.KeyID = signature.SSHSignature.KeyFingerprint
+	}
+	if signature.Signer != nil {
+		commitSignature.Signer = signature.Signer.Login
+	}
+
+	return commitSignature, nil
+}
+
+// checkCommitSignature checks if the commit that the action is pinned to is signed by an expected signer.
+// It returns a lint error message and an error kind if the commit violates the policy.
+func (l *linter) checkCommitSignature(a Action, params *WorkflowLintParams) (string, ErrorKind, error) {
+	if params.RequireSignedCommits == nil || !*params.RequireSignedCommits {
+		return "", "", nil
+	}
+
+	if slices.Contains(params.SignedCommitExemptOwners, a.Owner) {
+		return "", "", nil
+	}
+
+	signature, err := l.getCommitSignature(a)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get the commit signature: %w", err)
+	}
+
+	if signature == nil {
+		return fmt.Sprintf("pinned commit is not signed: action=%s, sha=%s", a.RepoID(), shortenHash(a.Ref)), KindUnsignedCommit, nil
+	}
+
+	if !signature.IsValid {
+		return fmt.Sprintf("pinned commit has an invalid signature: action=%s, sha=%s, state=%s", a.RepoID(), shortenHash(a.Ref), signature.State),
+			KindUnsignedCommit, nil
+	}
+
+	expectedSigners := params.GetExpectedSigners(a)
+	if len(expectedSigners) > 0 && !signature.MatchesAny(expectedSigners) {
+		return fmt.Sprintf("pinned commit is signed by an unexpected signer: action=%s, sha=%s, signer=%s, expected=%v",
+			a.RepoID(), shortenHash(a.Ref), signature, expectedSigners), KindUnexpectedSigner, nil
+	}
+
+	return "", "", nil
+}
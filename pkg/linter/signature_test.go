@@ -0,0 +1,59 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitSignature_MatchesAny(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name      string
+		signature CommitSignature
+		expected  []string
+		want      bool
+	}{
+		{
+			name:      "login",
+			signature: CommitSignature{IsValid: true, Signer: "octocat"},
+			expected:  []string{"OctoCat"},
+			want:      true,
+		},
+		{
+			name:      "key id",
+			signature: CommitSignature{IsValid: true, Signer: "octocat", KeyID: "4AEE18F83AFDEB23"},
+			expected:  []string{"4aee18f83afdeb23"},
+			want:      true,
+		},
+		{
+			name:      "email",
+			signature: CommitSignature{IsValid: true, Email: "octocat@example.com"},
+			expected:  []string{"someone", " octocat@example.com "},
+			want:      true,
+		},
+		{
+			name:      "signed by GitHub",
+			signature: CommitSignature{IsValid: true, Email: "noreply@github.com", WasSignedByGitHub: true},
+			expected:  []string{"web-flow"},
+			want:      true,
+		},
+		{
+			name:      "unexpected signer",
+			signature: CommitSignature{IsValid: true, Signer: "someone", KeyID: "0123456789ABCDEF"},
+			expected:  []string{"octocat", "web-flow"},
+			want:      false,
+		},
+		{
+			name:      "no identity",
+			signature: CommitSignature{IsValid: true},
+			expected:  []string{"octocat"},
+			want:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		a.Equal(tc.want, tc.signature.MatchesAny(tc.expected), tc.name)
+	}
+}
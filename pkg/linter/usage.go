@@ -0,0 +1,261 @@
+package linter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rhysd/actionlint"
+)
+
+// UsesKind represents a kind of 'uses' value.
+type UsesKind string
+
+const (
+	// UsesKindAction is an action or a reusable workflow in a repository: OWNER/REPO[/PATH]@REF
+	UsesKindAction UsesKind = "action"
+
+	// UsesKindLocal is an action or a reusable workflow in the same repository: ./PATH
+	UsesKindLocal UsesKind = "local"
+
+	// UsesKindDocker is a Docker container image: docker://IMAGE
+	UsesKindDocker UsesKind = "docker"
+)
+
+const dockerUsesPrefix = "docker://"
+
+// ActionUsage represents a location of an action used in a workflow.
+type ActionUsage struct {
+	// Kind is a kind of the 'uses' value.
+	Kind UsesKind
+
+	// Uses is the 'uses' value.
+	Uses string
+
+	// Action is the action parsed from the 'uses' value.
+	// It is a zero value unless the Kind is UsesKindAction.
+	Action Action
+
+	// RepoID is a repository ID (OWNER/NAME) of the workflow.
+	RepoID string
+
+	// Rev is a Git revision that the workflow was read from.
+	Rev string
+
+	// FilePath is a path to the workflow file relative to the project root.
+	FilePath string
+
+	// JobID is an ID of the job that uses the action.
+	JobID string
+
+	// StepName is a name of the step that uses the action.
+	// It is empty when the step has no name or the action is a reusable workflow called by a job.
+	StepName string
+
+	// StepIndex is a zero-based index of the step in the job.
+	// It is -1 when the action is a reusable workflow called by a job.
+	StepIndex int
+
+	// Pos is a position of the 'uses' value.
+	Pos *actionlint.Pos
+}
+
+// DockerImage returns the image of a 'docker://' value. It returns an empty string for the other kinds.
+func (u ActionUsage) DockerImage() string {
+	if u.Kind != UsesKindDocker {
+		return ""
+	}
+
+	return strings.TrimPrefix(u.Uses, dockerUsesPrefix)
+}
+
+// DisplayPath returns a path of the workflow file with the repository ID: OWNER/NAME/PATH or OWNER/NAME@REV:PATH.
+func (u ActionUsage) DisplayPath() string {
+	return FormatDisplayPath(u.RepoID, u.Rev, u.FilePath)
+}
+
+// Step returns a display name of the step: the step name if exists, otherwise '#INDEX'.
+func (u ActionUsage) Step() string {
+	if u.StepIndex < 0 {
+		return ""
+	}
+
+	if u.StepName != "" {
+		return u.StepName
+	}
+
+	return fmt.Sprintf("#%d", u.StepIndex)
+}
+
+// ActionDetail represents information of an action repository at a ref.
+type ActionDetail struct {
+	// CommitHash is a commit hash of the ref.
+	CommitHash string
+
+	// Tags is a list of git tags that point to the commit.
+	Tags []string
+
+	// Archived is true if the action repository is archived.
+	Archived bool
+
+	// ArchivedAt is a time when the action repository was archived.
+	ArchivedAt *time.Time
+}
+
+// ParseUses parses a 'uses' value into an ActionUsage without a location.
+// The host of the action is determined by params if it is not nil.
+func ParseUses(uses string, params *WorkflowLintParams) (*ActionUsage, error) {
+	usage := &ActionUsage{
+		Uses:      strings.TrimSpace(uses),
+		StepIndex: -1,
+	}
+
+	switch {
+	case strings.HasPrefix(usage.Uses, dockerUsesPrefix):
+		usage.Kind = UsesKindDocker
+	case strings.HasPrefix(usage.Uses, "./"):
+		usage.Kind = UsesKindLocal
+	default:
+		action, err := ParseActionUses(usage.Uses)
+		if err != nil {
+			return nil, err
+		}
+
+		if params != nil {
+			action.Host = params.GetHost(action.Owner)
+		}
+
+		usage.Kind = UsesKindAction
+		usage.Action = *action
+	}
+
+	return usage, nil
+}
+
+func newActionUsage(uses *actionlint.String, wfLintInfo WorkflowLintInfo, relPath, jobID string) *ActionUsage {
+	if uses == nil || !wfLintInfo.IsTarget(uses) {
+		return nil
+	}
+
+	usage, err := ParseUses(uses.Value, wfLintInfo.Params)
+	if err != nil {
+		return nil
+	}
+
+	usage.RepoID = wfLintInfo.RepoID
+	usage.Rev = wfLintInfo.Rev
+	usage.FilePath = relPath
+	usage.JobID = jobID
+	usage.Pos = uses.Pos
+
+	return usage
+}
+
+// ListActionUsages returns the actions used in a workflow in the order of their positions.
+// content must be the body of the workflow file.
+// Local actions, local reusable workflows, and Docker container actions are excluded.
+func ListActionUsages(wfLintInfo WorkflowLintInfo, content []byte) ([]*ActionUsage, error) {
+	usages, err := ListUsages(wfLintInfo, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(usages, func(u *ActionUsage) bool {
+		return u.Kind != UsesKindAction
+	}), nil
+}
+
+// ListUsages returns all of the 'uses' values in a workflow in the order of their positions.
+// content must be the body of the workflow file.
+func ListUsages(wfLintInfo WorkflowLintInfo, content []byte) ([]*ActionUsage, error) {
+	workflow, parseErrors := actionlint.Parse(content)
+	if len(parseErrors) > 0 {
+		msgs := make([]string, 0, len(parseErrors))
+		for _, e := range parseErrors {
+			msgs = append(msgs, e.Error())
+		}
+
+		return nil, fmt.Errorf("failed to parse workflow: path=%s, msg=%s", wfLintInfo.FilePath, strings.Join(msgs, "\n"))
+	}
+
+	relPath, err := wfLintInfo.RelPath()
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]*ActionUsage, 0)
+
+	for jobID, job := range workflow.Jobs {
+		if job.WorkflowCall != nil {
+			if usage := newActionUsage(job.WorkflowCall.Uses, wfLintInfo, relPath, jobID); usage != nil {
+				usages = append(usages, usage)
+			}
+		}
+
+		for i, step := range job.Steps {
+			exec, ok := step.Exec.(*actionlint.ExecAction)
+			if !ok {
+				continue
+			}
+
+			usage := newActionUsage(exec.Uses, wfLintInfo, relPath, jobID)
+			if usage == nil {
+				continue
+			}
+
+			if step.Name != nil {
+				usage.StepName = step.Name.Value
+			}
+			usage.StepIndex = i
+
+			usages = append(usages, usage)
+		}
+	}
+
+	slices.SortFunc(usages, func(a, b *ActionUsage) int {
+		if a.Pos.Line != b.Pos.Line {
+			return a.Pos.Line - b.Pos.Line
+		}
+
+		return a.Pos.Col - b.Pos.Col
+	})
+
+	return usages, nil
+}
+
+// DescribeActionContext returns information of an action repository at the ref of the action.
+// It returns ErrOffline in the offline mode.
+func (l *linter) DescribeActionContext(ctx context.Context, action Action) (*ActionDetail, error) {
+	if l.offline {
+		return nil, ErrOffline
+	}
+
+	archived, archivedAt, err := l.isArchivedAction(action)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if the action is archived: %w", err)
+	}
+
+	detail := &ActionDetail{
+		CommitHash: action.Ref,
+		Archived:   archived,
+		ArchivedAt: archivedAt,
+	}
+
+	if !action.IsPinnedBySHA() {
+		gitTag, err := l.resolveGitTag(ctx, action.Repository(), action.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve git tag: %w", err)
+		}
+
+		detail.CommitHash = gitTag.CommitHash
+	}
+
+	detail.Tags, err = l.resolveGitTagNamesFromSha(ctx, action.Repository(), detail.CommitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return detail, nil
+}
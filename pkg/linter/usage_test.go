@@ -0,0 +1,85 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAction_SubPath(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		uses string
+		want string
+	}{
+		{uses: "owner/repo@v1", want: ""},
+		{uses: "owner/repo/sub/dir@v1", want: "sub/dir"},
+		{uses: "octo-org/this-repo/.github/workflows/workflow-1.yml@v1", want: ".github/workflows/workflow-1.yml"},
+	}
+
+	for _, tc := range testCases {
+		action, err := ParseActionUses(tc.uses)
+		a.NoError(err, tc.uses)
+		a.Equal(tc.want, action.SubPath(), tc.uses)
+	}
+}
+
+func TestListActionUsages(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	content := []byte(`on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - name: build and push
+        uses: docker/build-push-action@0565240e2d4ab88bba5387d719585280857ece09
+      - uses: ./local-action
+      - uses: docker://alpine:3.8
+      - run: echo hello
+  call:
+    uses: octo-org/repo/.github/workflows/reusable.yml@v1
+`)
+	wfLintInfo := WorkflowLintInfo{
+		FilePath: ".github/workflows/ci.yaml",
+		RepoID:   "owner/repo",
+		Rev:      "main",
+	}
+
+	usages, err := ListActionUsages(wfLintInfo, content)
+	r.NoError(err)
+	r.Len(usages, 3)
+
+	a.Equal("actions/checkout", usages[0].Action.ID)
+	a.Equal("build", usages[0].JobID)
+	a.Equal(0, usages[0].StepIndex)
+	a.Equal("#0", usages[0].Step())
+	a.Equal(6, usages[0].Pos.Line)
+	a.Equal("owner/repo@main:.github/workflows/ci.yaml", usages[0].DisplayPath())
+
+	a.Equal("docker/build-push-action", usages[1].Action.ID)
+	a.Equal("build and push", usages[1].Step())
+	a.True(usages[1].Action.IsPinnedBySHA())
+
+	a.Equal("octo-org/repo/.github/workflows/reusable.yml", usages[2].Action.ID)
+	a.Equal("call", usages[2].JobID)
+	a.Equal(-1, usages[2].StepIndex)
+	a.Empty(usages[2].Step())
+
+	_, err = ListActionUsages(wfLintInfo, []byte("jobs: ["))
+	a.Error(err)
+
+	usages, err = ListUsages(wfLintInfo, content)
+	r.NoError(err)
+	r.Len(usages, 5)
+
+	a.Equal(UsesKindLocal, usages[2].Kind)
+	a.Equal("./local-action", usages[2].Uses)
+	a.Equal(UsesKindDocker, usages[3].Kind)
+	a.Equal("alpine:3.8", usages[3].DockerImage())
+	a.Empty(usages[0].DockerImage())
+}
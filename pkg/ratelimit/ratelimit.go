@@ -0,0 +1,402 @@
+// Package ratelimit provides an HTTP transport that is aware of the GitHub API rate limits.
+// ref: https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api
+// ref: https://docs.github.com/en/graphql/overview/rate-limits-and-node-limits-for-the-graphql-api
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	headerRetryAfter = "Retry-After"
+	headerLimit      = "X-Ratelimit-Limit"
+	headerRemaining  = "X-Ratelimit-Remaining"
+	headerReset      = "X-Ratelimit-Reset"
+
+	// DefaultMaxRetries is the default maximum number of retries of a rate limited request.
+	DefaultMaxRetries = 3
+
+	// DefaultBaseDelay is the default delay before the first retry when a response has no hint of a delay.
+	// GitHub recommends waiting for at least one minute before retrying on a secondary rate limit.
+	DefaultBaseDelay = time.Minute
+
+	// DefaultMaxDelay is the default maximum delay of a retry.
+	// Requests are not retried if they are required to wait for longer than the delay.
+	DefaultMaxDelay = 5 * time.Minute
+
+	// maxPeekBodySize is the maximum size of a response body to inspect for rate limit errors.
+	maxPeekBodySize = 64 * 1024
+)
+
+// Params is a set of parameters for New.
+type Params struct {
+	// Transport is the underlying transport. http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+
+	// Logger is a logger.
+	Logger *slog.Logger
+
+	// MaxRetries is the maximum number of retries of a rate limited request.
+	// DefaultMaxRetries is used if the value is zero. Requests are not retried if the value is negative.
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry when a response has no hint of a delay.
+	// The delay is doubled for each retry. DefaultBaseDelay is used if the value is not positive.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay of a retry. DefaultMaxDelay is used if the value is not positive.
+	MaxDelay time.Duration
+}
+
+// Usage represents the API usage of a host in a run.
+type Usage struct {
+	// Host is a GitHub host.
+	Host string
+
+	// Requests is the number of requests sent to the host, including retries.
+	Requests int
+
+	// Retries is the number of retries of rate limited requests.
+	Retries int
+
+	// RateLimited is the number of rate limited responses.
+	RateLimited int
+
+	// Limit is the latest maximum number of points (GraphQL) or requests (REST) per hour. It is -1 if unknown.
+	Limit int
+
+	// Remaining is the latest number of remaining points or requests in the current window. It is -1 if unknown.
+	Remaining int
+
+	// ResetAt is the time when the current rate limit window resets. It is nil if unknown.
+	ResetAt *time.Time
+}
+
+func (u Usage) String() string {
+	return fmt.Sprintf("host=%s, requests=%d, retries=%d, rate-limited=%d, remaining=%d/%d",
+		u.Host, u.Requests, u.Retries, u.RateLimited, u.Remaining, u.Limit)
+}
+
+// Transport is an http.RoundTripper that tracks the rate limits of the GitHub API,
+// and backs off and retries requests that are rate limited.
+// A Transport can be shared by clients of multiple hosts: the usage is tracked per host.
+type Transport struct {
+	transport  http.RoundTripper
+	logger     *slog.Logger
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+
+	mu     sync.Mutex
+	usages map[string]*Usage
+
+	// now and sleep are replaceable for tests
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New creates a new rate limit aware transport.
+func New(params *Params) *Transport {
+	t := &Transport{
+		transport:  params.Transport,
+		logger:     params.Logger,
+		maxRetries: params.MaxRetries,
+		baseDelay:  params.BaseDelay,
+		maxDelay:   params.MaxDelay,
+		usages:     map[string]*Usage{},
+		now:        time.Now,
+		sleep:      sleepContext,
+	}
+
+	if t.transport == nil {
+		t.transport = http.DefaultTransport
+	}
+	if t.logger == nil {
+		t.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	if t.maxRetries == 0 {
+		t.maxRetries = DefaultMaxRetries
+	}
+	if t.baseDelay <= 0 {
+		t.baseDelay = DefaultBaseDelay
+	}
+	if t.maxDelay <= 0 {
+		t.maxDelay = DefaultMaxDelay
+	}
+
+	return t
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Usages returns the API usages of the hosts in the order of the host names.
+func (t *Transport) Usages() []Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usages := make([]Usage, 0, len(t.usages))
+	for _, usage := range t.usages {
+		usages = append(usages, *usage)
+	}
+
+	slices.SortFunc(usages, func(a, b Usage) int {
+		return strings.Compare(a.Host, b.Host)
+	})
+
+	return usages
+}
+
+// usage returns the usage of a host. t.mu must be held.
+func (t *Transport) usage(host string) *Usage {
+	usage, exist := t.usages[host]
+	if !exist {
+		usage = &Usage{
+			Host:      host,
+			Limit:     -1,
+			Remaining: -1,
+		}
+		t.usages[host] = usage
+	}
+
+	return usage
+}
+
+// waitForReset returns the duration to wait before sending a request to a host whose quota is exhausted.
+func (t *Transport) waitForReset(host string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := t.usage(host)
+	if usage.Remaining != 0 || usage.ResetAt == nil {
+		return 0
+	}
+
+	return max(usage.ResetAt.Sub(t.now()), 0)
+}
+
+// record updates the usage of a host with a response.
+func (t *Transport) record(host string, resp *http.Response, rateLimited bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := t.usage(host)
+	usage.Requests++
+	if rateLimited {
+		usage.RateLimited++
+	}
+
+	if resp == nil {
+		return
+	}
+
+	if v, err := strconv.Atoi(resp.Header.Get(headerLimit)); err == nil {
+		usage.Limit = v
+	}
+	if v, err := strconv.Atoi(resp.Header.Get(headerRemaining)); err == nil {
+		usage.Remaining = v
+	}
+	if v, err := strconv.ParseInt(resp.Header.Get(headerReset), 10, 64); err == nil {
+		resetAt := time.Unix(v, 0)
+		usage.ResetAt = &resetAt
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	host := req.URL.Host
+
+	if d := t.waitForReset(host); d > 0 {
+		if d > t.maxDelay {
+			return nil, fmt.Errorf("rate limit exceeded: host=%s, reset-in=%s", host, d.Round(time.Second))
+		}
+
+		t.logger.Warn("rate limit exceeded, waiting for the reset",
+			slog.String("host", host),
+			slog.Duration("wait", d.Round(time.Second)),
+		)
+		if err := t.sleep(ctx, d); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			body, err := rewindBody(req)
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil {
+			t.record(host, nil, false)
+			return nil, err
+		}
+
+		rateLimited, err := isRateLimited(resp)
+		if err != nil {
+			t.record(host, resp, false)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		t.record(host, resp, rateLimited)
+		if !rateLimited {
+			return resp, nil
+		}
+
+		delay := t.retryDelay(resp, attempt)
+		if attempt >= t.maxRetries || delay > t.maxDelay || !isRewindable(req) {
+			t.logger.Warn("rate limited, giving up",
+				slog.String("host", host),
+				slog.Int("status", resp.StatusCode),
+				slog.Int("attempts", attempt+1),
+				slog.Duration("delay", delay.Round(time.Second)),
+			)
+			return resp, nil
+		}
+
+		t.logger.Warn("rate limited, retrying",
+			slog.String("host", host),
+			slog.Int("status", resp.StatusCode),
+			slog.Int("retry", attempt+1),
+			slog.Duration("delay", delay.Round(time.Second)),
+		)
+
+		// drain the body to reuse the connection
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err := t.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+
+		t.mu.Lock()
+		t.usage(host).Retries++
+		t.mu.Unlock()
+	}
+}
+
+// isRewindable returns true if a request can be sent again.
+func isRewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func rewindBody(req *http.Request) (io.ReadCloser, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req.Body, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind a request body: %w", err)
+	}
+
+	return body, nil
+}
+
+// retryDelay returns the delay before retrying a rate limited request.
+// The delay is taken from the Retry-After header, or the X-RateLimit-Reset header if the quota is exhausted.
+// Otherwise, the delay increases exponentially from the base delay.
+func (t *Transport) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if v := resp.Header.Get(headerRetryAfter); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(v); err == nil {
+			return max(date.Sub(t.now()), 0)
+		}
+	}
+
+	if resp.Header.Get(headerRemaining) == "0" {
+		if v, err := strconv.ParseInt(resp.Header.Get(headerReset), 10, 64); err == nil {
+			return max(time.Unix(v, 0).Sub(t.now()), 0)
+		}
+	}
+
+	return t.baseDelay << attempt
+}
+
+// isRateLimited returns true if a response is rate limited:
+//   - 429 responses
+//   - 403 responses with a Retry-After header, an exhausted quota, or a secondary rate limit (abuse detection) message
+//   - GraphQL responses with RATE_LIMITED errors, which are returned with the 200 status code
+//
+// The response body is restored after it is inspected.
+func isRateLimited(resp *http.Response) (bool, error) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true, nil
+	case http.StatusForbidden:
+		if resp.Header.Get(headerRetryAfter) != "" || resp.Header.Get(headerRemaining) == "0" {
+			return true, nil
+		}
+
+		body, err := peekBody(resp)
+		if err != nil {
+			return false, err
+		}
+		msg := strings.ToLower(string(body))
+
+		return strings.Contains(msg, "rate limit") || strings.Contains(msg, "abuse"), nil
+	case http.StatusOK:
+		// GraphQL rate limit errors can be returned only if the quota is exhausted
+		if resp.Header.Get(headerRemaining) != "0" {
+			return false, nil
+		}
+
+		body, err := peekBody(resp)
+		if err != nil {
+			return false, err
+		}
+
+		return bytes.Contains(body, []byte(`"RATE_LIMITED"`)), nil
+	}
+
+	return false, nil
+}
+
+// peekBody reads the head of a response body and restores the body.
+func peekBody(resp *http.Response) ([]byte, error) {
+	if resp.Body == nil {
+		return nil, nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, maxPeekBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read a response body: %w", err)
+	}
+
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(head), resp.Body),
+		Closer: resp.Body,
+	}
+
+	return head, nil
+}
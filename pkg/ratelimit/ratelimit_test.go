@@ -0,0 +1,319 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+type fakeTransport struct {
+	responses []fakeResponse
+	bodies    []string
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		f.bodies = append(f.bodies, string(body))
+	}
+
+	r := f.responses[0]
+	if len(f.responses) > 1 {
+		f.responses = f.responses[1:]
+	}
+
+	header := http.Header{}
+	for k, v := range r.headers {
+		header.Set(k, v)
+	}
+
+	return &http.Response{
+		StatusCode: r.status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(r.body)),
+		Request:    req,
+	}, nil
+}
+
+func newTestTransport(ft *fakeTransport, now time.Time, delays *[]time.Duration) *Transport {
+	t := New(&Params{
+		Transport: ft,
+		BaseDelay: time.Second,
+		MaxDelay:  time.Minute,
+	})
+	t.now = func() time.Time { return now }
+	t.sleep = func(_ context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+
+	return t
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	now := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	reset := strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)
+	ok := fakeResponse{
+		status:  http.StatusOK,
+		headers: map[string]string{headerLimit: "5000", headerRemaining: "4999", headerReset: reset},
+		body:    `{"data":{}}`,
+	}
+
+	testCases := []struct {
+		name          string
+		responses     []fakeResponse
+		wantStatus    int
+		wantDelays    []time.Duration
+		wantRetries   int
+		wantLimited   int
+		wantRemaining int
+		wantNumBodies int
+	}{
+		{
+			name:          "not rate limited",
+			responses:     []fakeResponse{ok},
+			wantStatus:    http.StatusOK,
+			wantDelays:    nil,
+			wantRemaining: 4999,
+			wantNumBodies: 1,
+		},
+		{
+			name: "retry after",
+			responses: []fakeResponse{
+				{status: http.StatusTooManyRequests, headers: map[string]string{headerRetryAfter: "7"}},
+				ok,
+			},
+			wantStatus:    http.StatusOK,
+			wantDelays:    []time.Duration{7 * time.Second},
+			wantRetries:   1,
+			wantLimited:   1,
+			wantRemaining: 4999,
+			wantNumBodies: 2,
+		},
+		{
+			name: "exhausted quota",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, headers: map[string]string{headerRemaining: "0", headerReset: reset}},
+				ok,
+			},
+			wantStatus:    http.StatusOK,
+			wantDelays:    []time.Duration{30 * time.Second},
+			wantRetries:   1,
+			wantLimited:   1,
+			wantRemaining: 4999,
+			wantNumBodies: 2,
+		},
+		{
+			name: "secondary rate limit with exponential backoff",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, body: `{"message":"You have exceeded a secondary rate limit."}`},
+				{status: http.StatusForbidden, body: `{"message":"You have triggered an abuse detection mechanism."}`},
+				ok,
+			},
+			wantStatus:    http.StatusOK,
+			wantDelays:    []time.Duration{time.Second, 2 * time.Second},
+			wantRetries:   2,
+			wantLimited:   2,
+			wantRemaining: 4999,
+			wantNumBodies: 3,
+		},
+		{
+			name: "GraphQL rate limited",
+			responses: []fakeResponse{
+				{
+					status:  http.StatusOK,
+					headers: map[string]string{headerRemaining: "0", headerReset: reset},
+					body:    `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`,
+				},
+				ok,
+			},
+			wantStatus:    http.StatusOK,
+			wantDelays:    []time.Duration{30 * time.Second},
+			wantRetries:   1,
+			wantLimited:   1,
+			wantRemaining: 4999,
+			wantNumBodies: 2,
+		},
+		{
+			name: "give up",
+			responses: []fakeResponse{
+				{status: http.StatusTooManyRequests, headers: map[string]string{headerRetryAfter: "1"}},
+			},
+			wantStatus:    http.StatusTooManyRequests,
+			wantDelays:    []time.Duration{time.Second, time.Second, time.Second},
+			wantRetries:   3,
+			wantLimited:   4,
+			wantRemaining: -1,
+			wantNumBodies: 4,
+		},
+		{
+			name: "retry after longer than the max delay",
+			responses: []fakeResponse{
+				{status: http.StatusTooManyRequests, headers: map[string]string{headerRetryAfter: "3600"}},
+			},
+			wantStatus:    http.StatusTooManyRequests,
+			wantDelays:    nil,
+			wantLimited:   1,
+			wantRemaining: -1,
+			wantNumBodies: 1,
+		},
+		{
+			name:          "forbidden",
+			responses:     []fakeResponse{{status: http.StatusForbidden, body: `{"message":"Resource not accessible"}`}},
+			wantStatus:    http.StatusForbidden,
+			wantDelays:    nil,
+			wantRemaining: -1,
+			wantNumBodies: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			r := require.New(t)
+
+			ft := &fakeTransport{responses: tc.responses}
+			var delays []time.Duration
+			transport := newTestTransport(ft, now, &delays)
+
+			req, err := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", strings.NewReader(`{"query":"{}"}`))
+			r.NoError(err)
+
+			resp, err := transport.RoundTrip(req)
+			r.NoError(err)
+			defer resp.Body.Close()
+			a.Equal(tc.wantStatus, resp.StatusCode)
+
+			// the body of an inspected response is restored
+			body, err := io.ReadAll(resp.Body)
+			r.NoError(err)
+			a.Equal(tc.responses[len(tc.responses)-1].body, string(body))
+
+			a.Equal(tc.wantDelays, delays)
+
+			// request bodies are rewound for retries
+			a.Len(ft.bodies, tc.wantNumBodies)
+			for _, body := range ft.bodies {
+				a.Equal(`{"query":"{}"}`, body)
+			}
+
+			usages := transport.Usages()
+			r.Len(usages, 1)
+			a.Equal("api.github.com", usages[0].Host)
+			a.Equal(tc.wantRetries, usages[0].Retries)
+			a.Equal(tc.wantLimited, usages[0].RateLimited)
+			a.Equal(tc.wantRemaining, usages[0].Remaining)
+		})
+	}
+}
+
+func TestTransport_WaitForReset(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	now := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	ft := &fakeTransport{responses: []fakeResponse{
+		{
+			status:  http.StatusOK,
+			headers: map[string]string{headerLimit: "5000", headerRemaining: "0", headerReset: strconv.FormatInt(now.Add(time.Minute).Unix(), 10)},
+			body:    `{"data":{}}`,
+		},
+		{
+			status:  http.StatusOK,
+			headers: map[string]string{headerLimit: "5000", headerRemaining: "4999", headerReset: strconv.FormatInt(now.Add(time.Hour).Unix(), 10)},
+			body:    `{"data":{}}`,
+		},
+	}}
+	var delays []time.Duration
+	transport := newTestTransport(ft, now, &delays)
+
+	for range 2 {
+		req, err := http.NewRequest(http.MethodGet, "https://api.github.com/rate_limit", nil)
+		r.NoError(err)
+
+		resp, err := transport.RoundTrip(req)
+		r.NoError(err)
+		resp.Body.Close()
+		a.Equal(http.StatusOK, resp.StatusCode)
+	}
+
+	// the second request waits for the reset of the exhausted quota
+	a.Equal([]time.Duration{time.Minute}, delays)
+
+	usages := transport.Usages()
+	r.Len(usages, 1)
+	a.Equal(2, usages[0].Requests)
+	a.Equal(5000, usages[0].Limit)
+	a.Equal(4999, usages[0].Remaining)
+
+	// requests fail without waiting if the reset is beyond the max delay
+	transport.usage("api.github.com").Remaining = 0
+	req, err := http.NewRequest(http.MethodGet, "https://api.github.com/rate_limit", nil)
+	r.NoError(err)
+	_, err = transport.RoundTrip(req)
+	a.EqualError(err, "rate limit exceeded: host=api.github.com, reset-in=1h0m0s")
+}
+
+// failingBody is a response body that fails to be read.
+type failingBody struct {
+	closed bool
+}
+
+func (b *failingBody) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func (b *failingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransport_RoundTrip_BodyReadError(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	body := &failingBody{}
+	transport := New(&Params{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{},
+				Body:       body,
+				Request:    req,
+			}, nil
+		}),
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.github.com/rate_limit", nil)
+	r.NoError(err)
+
+	resp, err := transport.RoundTrip(req)
+	a.Nil(resp)
+	a.ErrorContains(err, "failed to read a response body")
+
+	// the body of the discarded response is closed
+	a.True(body.closed)
+}
@@ -0,0 +1,146 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+)
+
+const (
+	cycloneDXSpecVersion = "1.5"
+
+	// cycloneDXRootRef is a BomRef of the subject of the document.
+	cycloneDXRootRef = "root"
+
+	propertyCommitHash = common.ToolName + ":commit-hash"
+	propertyTag        = common.ToolName + ":tag"
+	propertyPath       = common.ToolName + ":path"
+)
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cycloneDXComponent struct {
+	BomRef             string                       `json:"bom-ref"`
+	Type               string                       `json:"type"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	Purl               string                       `json:"purl,omitempty"`
+	ExternalReferences []cycloneDXExternalReference `json:"externalReferences,omitempty"`
+	Properties         []cycloneDXProperty          `json:"properties,omitempty"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+type cycloneDXDocument struct {
+	BomFormat    string `json:"bomFormat"`
+	SpecVersion  string `json:"specVersion"`
+	SerialNumber string `json:"serialNumber"`
+	Version      int    `json:"version"`
+	Metadata     struct {
+		Timestamp string `json:"timestamp"`
+		Tools     struct {
+			Components []cycloneDXComponent `json:"components"`
+		} `json:"tools"`
+		Component cycloneDXComponent `json:"component"`
+	} `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+func toCycloneDXType(t ComponentType) string {
+	switch t {
+	case ComponentTypeWorkflow:
+		return "file"
+	case ComponentTypeContainer:
+		return "container"
+	default:
+		return "application"
+	}
+}
+
+func toCycloneDXComponent(c *Component) cycloneDXComponent {
+	component := cycloneDXComponent{
+		BomRef:  c.BomRef,
+		Type:    toCycloneDXType(c.Type),
+		Name:    c.Name,
+		Version: c.Version,
+		Purl:    c.Purl,
+	}
+
+	if c.VCSURL != "" {
+		component.ExternalReferences = append(component.ExternalReferences, cycloneDXExternalReference{
+			Type: "vcs",
+			URL:  c.VCSURL,
+		})
+	}
+
+	if c.SubPath != "" {
+		component.Properties = append(component.Properties, cycloneDXProperty{Name: propertyPath, Value: c.SubPath})
+	}
+	if c.CommitHash != "" {
+		component.Properties = append(component.Properties, cycloneDXProperty{Name: propertyCommitHash, Value: c.CommitHash})
+	}
+	for _, tag := range c.Tags {
+		component.Properties = append(component.Properties, cycloneDXProperty{Name: propertyTag, Value: tag})
+	}
+
+	return component
+}
+
+// WriteCycloneDX writes the document in the CycloneDX JSON format.
+func (d Document) WriteCycloneDX(w io.Writer) error {
+	var doc cycloneDXDocument
+
+	doc.BomFormat = "CycloneDX"
+	doc.SpecVersion = cycloneDXSpecVersion
+	doc.SerialNumber = "urn:uuid:" + d.SerialNumber.String()
+	doc.Version = 1
+	doc.Metadata.Timestamp = d.Timestamp.UTC().Format(time.RFC3339)
+	doc.Metadata.Tools.Components = []cycloneDXComponent{
+		{
+			BomRef: toolName,
+			Type:   "application",
+			Name:   toolName,
+		},
+	}
+	doc.Metadata.Component = cycloneDXComponent{
+		BomRef: cycloneDXRootRef,
+		Type:   "application",
+		Name:   d.Name,
+	}
+
+	components := d.Components()
+	doc.Components = make([]cycloneDXComponent, 0, len(components))
+	doc.Dependencies = make([]cycloneDXDependency, 0, len(components)+1)
+
+	doc.Dependencies = append(doc.Dependencies, cycloneDXDependency{
+		Ref:       cycloneDXRootRef,
+		DependsOn: d.Roots(),
+	})
+
+	for _, c := range components {
+		doc.Components = append(doc.Components, toCycloneDXComponent(c))
+		doc.Dependencies = append(doc.Dependencies, cycloneDXDependency{
+			Ref:       c.BomRef,
+			DependsOn: d.DependsOn(c.BomRef),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
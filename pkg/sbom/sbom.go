@@ -0,0 +1,244 @@
+// Package sbom provides SBOM (Software Bill of Materials) documents of GitHub Actions workflow dependencies.
+package sbom
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+)
+
+// ComponentType represents a type of a component.
+type ComponentType string
+
+const (
+	// ComponentTypeWorkflow is a GitHub Actions workflow file.
+	ComponentTypeWorkflow ComponentType = "workflow"
+
+	// ComponentTypeAction is an action or a reusable workflow.
+	ComponentTypeAction ComponentType = "action"
+
+	// ComponentTypeContainer is a Docker container image.
+	ComponentTypeContainer ComponentType = "container"
+)
+
+const defaultGitHubHost = "github.com"
+
+// toolName is a name of the tool that creates the documents.
+var toolName = "gh-" + common.ToolName
+
+// Component represents a component of an SBOM document.
+type Component struct {
+	// BomRef is a unique reference of the component in the document.
+	BomRef string
+
+	// Type is a type of the component.
+	Type ComponentType
+
+	// Name is a name of the component: e.g. OWNER/REPO[/PATH], a workflow file path, or an image name.
+	Name string
+
+	// Version is a version of the component: e.g. a git ref or an image tag.
+	Version string
+
+	// Purl is a package URL of the component.
+	Purl string
+
+	// CommitHash is a commit hash that the version points to.
+	CommitHash string
+
+	// Tags is a list of git tags that point to the commit.
+	Tags []string
+
+	// VCSURL is a URL of the repository of the component.
+	VCSURL string
+
+	// SubPath is a path in the repository of the component.
+	SubPath string
+}
+
+// Document represents an SBOM document that is independent of the output formats.
+type Document struct {
+	// Name is a name of the subject of the document.
+	Name string
+
+	// Timestamp is a time when the document was created.
+	Timestamp time.Time
+
+	// SerialNumber is a unique ID of the document.
+	SerialNumber uuid.UUID
+
+	components   map[string]*Component
+	dependencies map[string][]string
+	roots        []string
+}
+
+// NewDocument creates a new Document.
+func NewDocument(name string) *Document {
+	return &Document{
+		Name:         name,
+		Timestamp:    time.Now().UTC(),
+		SerialNumber: uuid.New(),
+		components:   make(map[string]*Component),
+		dependencies: make(map[string][]string),
+	}
+}
+
+// AddComponent adds a component to the document.
+// If a component with the same BomRef already exists, the existing component is returned.
+func (d *Document) AddComponent(c *Component) *Component {
+	if existing, exist := d.components[c.BomRef]; exist {
+		return existing
+	}
+
+	d.components[c.BomRef] = c
+
+	return c
+}
+
+// Component returns a component of the BomRef.
+func (d Document) Component(bomRef string) (*Component, bool) {
+	c, exist := d.components[bomRef]
+
+	return c, exist
+}
+
+// AddRoot adds a component as a direct dependency of the subject of the document.
+func (d *Document) AddRoot(c *Component) {
+	c = d.AddComponent(c)
+
+	if !slices.Contains(d.roots, c.BomRef) {
+		d.roots = append(d.roots, c.BomRef)
+	}
+}
+
+// AddDependency adds a dependency relationship between components.
+// Both of the components must be added to the document before calling this method.
+func (d *Document) AddDependency(from, to *Component) {
+	if slices.Contains(d.dependencies[from.BomRef], to.BomRef) {
+		return
+	}
+
+	d.dependencies[from.BomRef] = append(d.dependencies[from.BomRef], to.BomRef)
+}
+
+// Components returns the components sorted by the BomRef.
+func (d Document) Components() []*Component {
+	components := make([]*Component, 0, len(d.components))
+	for _, c := range d.components {
+		components = append(components, c)
+	}
+
+	slices.SortFunc(components, func(a, b *Component) int {
+		return strings.Compare(a.BomRef, b.BomRef)
+	})
+
+	return components
+}
+
+// Roots returns BomRefs of the direct dependencies of the subject of the document.
+func (d Document) Roots() []string {
+	return slices.Sorted(slices.Values(d.roots))
+}
+
+// DependsOn returns sorted BomRefs of the dependencies of a component.
+func (d Document) DependsOn(bomRef string) []string {
+	return slices.Sorted(slices.Values(d.dependencies[bomRef]))
+}
+
+func escapePurlSegment(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), ":", "%3A")
+}
+
+// ActionPurl returns a package URL of an action: pkg:githubactions/OWNER/REPO@REF[?repository_url=HOST][#SUBPATH]
+// The repository_url qualifier is added when the host is not github.com.
+func ActionPurl(host, owner, name, subPath, ref string) string {
+	purl := fmt.Sprintf("pkg:githubactions/%s/%s@%s", escapePurlSegment(owner), escapePurlSegment(name), escapePurlSegment(ref))
+
+	if host != "" && host != defaultGitHubHost {
+		purl += "?repository_url=" + url.QueryEscape(host)
+	}
+
+	if subPath != "" {
+		segments := strings.Split(strings.Trim(subPath, "/"), "/")
+		for i, segment := range segments {
+			segments[i] = escapePurlSegment(segment)
+		}
+
+		purl += "#" + strings.Join(segments, "/")
+	}
+
+	return purl
+}
+
+// DockerImage represents a reference of a Docker container image: [REGISTRY/]REPOSITORY[:TAG][@DIGEST]
+type DockerImage struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// Version returns the digest if exists, otherwise the tag.
+func (i DockerImage) Version() string {
+	if i.Digest != "" {
+		return i.Digest
+	}
+
+	return i.Tag
+}
+
+// ParseDockerImage parses a reference of a Docker container image.
+func ParseDockerImage(image string) DockerImage {
+	var ref DockerImage
+
+	image, ref.Digest, _ = strings.Cut(image, "@")
+
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image, ref.Tag = image[:i], image[i+1:]
+	}
+
+	if first, rest, found := strings.Cut(image, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry = first
+		image = rest
+	}
+
+	ref.Repository = image
+
+	return ref
+}
+
+// DockerPurl returns a package URL of a Docker container image: pkg:docker/[NAMESPACE/]NAME[@VERSION][?repository_url=REGISTRY]
+func DockerPurl(image string) string {
+	ref := ParseDockerImage(image)
+
+	segments := strings.Split(ref.Repository, "/")
+	for i, segment := range segments {
+		segments[i] = escapePurlSegment(segment)
+	}
+
+	purl := "pkg:docker/" + strings.Join(segments, "/")
+
+	if version := ref.Version(); version != "" {
+		purl += "@" + escapePurlSegment(version)
+	}
+
+	if ref.Registry != "" {
+		purl += "?repository_url=" + url.QueryEscape(ref.Registry)
+	}
+
+	return purl
+}
+
+// VCSURL returns a URL of a repository on a GitHub host.
+func VCSURL(host, owner, name string) string {
+	if host == "" {
+		host = defaultGitHubHost
+	}
+
+	return fmt.Sprintf("https://%s/%s/%s", host, owner, name)
+}
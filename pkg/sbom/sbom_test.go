@@ -0,0 +1,139 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionPurl(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		host    string
+		owner   string
+		name    string
+		subPath string
+		ref     string
+		want    string
+	}{
+		{
+			owner: "actions", name: "checkout", ref: "v4",
+			want: "pkg:githubactions/actions/checkout@v4",
+		},
+		{
+			host: "github.com", owner: "github", name: "codeql-action", subPath: "init", ref: "v3",
+			want: "pkg:githubactions/github/codeql-action@v3#init",
+		},
+		{
+			host: "github.example.com", owner: "org", name: "repo", subPath: ".github/workflows/reusable.yml", ref: "feature/x",
+			want: "pkg:githubactions/org/repo@feature%2Fx?repository_url=github.example.com#.github/workflows/reusable.yml",
+		},
+	}
+
+	for _, tc := range testCases {
+		a.Equal(tc.want, ActionPurl(tc.host, tc.owner, tc.name, tc.subPath, tc.ref))
+	}
+}
+
+func TestDockerPurl(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		image string
+		want  DockerImage
+		purl  string
+	}{
+		{
+			image: "alpine:3.8",
+			want:  DockerImage{Repository: "alpine", Tag: "3.8"},
+			purl:  "pkg:docker/alpine@3.8",
+		},
+		{
+			image: "ghcr.io/owner/image",
+			want:  DockerImage{Registry: "ghcr.io", Repository: "owner/image"},
+			purl:  "pkg:docker/owner/image?repository_url=ghcr.io",
+		},
+		{
+			image: "localhost:5000/image:v1@sha256:abc",
+			want:  DockerImage{Registry: "localhost:5000", Repository: "image", Tag: "v1", Digest: "sha256:abc"},
+			purl:  "pkg:docker/image@sha256%3Aabc?repository_url=localhost%3A5000",
+		},
+	}
+
+	for _, tc := range testCases {
+		a.Equal(tc.want, ParseDockerImage(tc.image), tc.image)
+		a.Equal(tc.purl, DockerPurl(tc.image), tc.image)
+	}
+}
+
+func newTestDocument() *Document {
+	doc := NewDocument("owner/repo")
+
+	wf := &Component{BomRef: "workflow:owner/repo/.github/workflows/ci.yaml", Type: ComponentTypeWorkflow, Name: "owner/repo/.github/workflows/ci.yaml"}
+	doc.AddRoot(wf)
+
+	action := doc.AddComponent(&Component{
+		BomRef:     "pkg:githubactions/actions/checkout@v4",
+		Type:       ComponentTypeAction,
+		Name:       "actions/checkout",
+		Version:    "v4",
+		Purl:       "pkg:githubactions/actions/checkout@v4",
+		CommitHash: "11bd71901bbe5b1630ceea73d27597364c9af683",
+		Tags:       []string{"v4", "v4.2.2"},
+		VCSURL:     "https://github.com/actions/checkout",
+	})
+	doc.AddDependency(wf, action)
+	doc.AddDependency(wf, action)
+
+	return doc
+}
+
+func TestDocument_WriteCycloneDX(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	var buf bytes.Buffer
+	r.NoError(newTestDocument().WriteCycloneDX(&buf))
+
+	var got cycloneDXDocument
+	r.NoError(json.Unmarshal(buf.Bytes(), &got))
+
+	a.Equal("CycloneDX", got.BomFormat)
+	a.Equal("owner/repo", got.Metadata.Component.Name)
+	r.Len(got.Components, 2)
+	a.Equal("application", got.Components[0].Type)
+	a.Equal("pkg:githubactions/actions/checkout@v4", got.Components[0].Purl)
+	a.Contains(got.Components[0].Properties, cycloneDXProperty{Name: propertyCommitHash, Value: "11bd71901bbe5b1630ceea73d27597364c9af683"})
+	a.Equal("file", got.Components[1].Type)
+
+	r.Len(got.Dependencies, 3)
+	a.Equal(cycloneDXDependency{Ref: cycloneDXRootRef, DependsOn: []string{"workflow:owner/repo/.github/workflows/ci.yaml"}}, got.Dependencies[0])
+	a.Equal([]string{"pkg:githubactions/actions/checkout@v4"}, got.Dependencies[2].DependsOn)
+}
+
+func TestDocument_WriteSPDX(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	var buf bytes.Buffer
+	r.NoError(newTestDocument().WriteSPDX(&buf))
+
+	var got spdxDocument
+	r.NoError(json.Unmarshal(buf.Bytes(), &got))
+
+	a.Equal(spdxVersion, got.SPDXVersion)
+	r.Len(got.Packages, 3)
+	a.Equal("git+https://github.com/actions/checkout@11bd71901bbe5b1630ceea73d27597364c9af683", got.Packages[1].DownloadLocation)
+	a.Equal("pkg:githubactions/actions/checkout@v4", got.Packages[1].ExternalRefs[0].ReferenceLocator)
+	a.Equal(spdxNoAssertion, got.Packages[2].DownloadLocation)
+
+	a.Equal([]spdxRelationship{
+		{SpdxElementID: spdxDocumentID, RelationshipType: "DESCRIBES", RelatedSpdxElement: spdxRootID},
+		{SpdxElementID: spdxRootID, RelationshipType: "DEPENDS_ON", RelatedSpdxElement: "SPDXRef-workflow-2"},
+		{SpdxElementID: "SPDXRef-workflow-2", RelationshipType: "DEPENDS_ON", RelatedSpdxElement: "SPDXRef-action-1"},
+	}, got.Relationships)
+}
@@ -0,0 +1,173 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	spdxVersion     = "SPDX-2.3"
+	spdxDocumentID  = "SPDXRef-DOCUMENT"
+	spdxRootID      = "SPDXRef-root"
+	spdxNoAssertion = "NOASSERTION"
+)
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxPackage struct {
+	SPDXID                string            `json:"SPDXID"`
+	Name                  string            `json:"name"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	Comment               string            `json:"comment,omitempty"`
+}
+
+type spdxRelationship struct {
+	SpdxElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+type spdxDocument struct {
+	SPDXVersion       string `json:"spdxVersion"`
+	DataLicense       string `json:"dataLicense"`
+	SPDXID            string `json:"SPDXID"`
+	Name              string `json:"name"`
+	DocumentNamespace string `json:"documentNamespace"`
+	CreationInfo      struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+	} `json:"creationInfo"`
+	Packages      []spdxPackage      `json:"packages"`
+	Relationships []spdxRelationship `json:"relationships"`
+}
+
+func toSPDXPurpose(t ComponentType) string {
+	switch t {
+	case ComponentTypeWorkflow:
+		return "FILE"
+	case ComponentTypeContainer:
+		return "CONTAINER"
+	default:
+		return "APPLICATION"
+	}
+}
+
+// toSPDXDownloadLocation returns a VCS locator of a component: git+https://HOST/OWNER/REPO@COMMIT[#SUBPATH]
+func toSPDXDownloadLocation(c *Component) string {
+	if c.VCSURL == "" {
+		return spdxNoAssertion
+	}
+
+	revision := c.CommitHash
+	if revision == "" {
+		revision = c.Version
+	}
+
+	location := fmt.Sprintf("git+%s@%s", c.VCSURL, revision)
+	if c.SubPath != "" {
+		location += "#" + c.SubPath
+	}
+
+	return location
+}
+
+func toSPDXPackage(id string, c *Component) spdxPackage {
+	pkg := spdxPackage{
+		SPDXID:                id,
+		Name:                  c.Name,
+		VersionInfo:           c.Version,
+		DownloadLocation:      toSPDXDownloadLocation(c),
+		PrimaryPackagePurpose: toSPDXPurpose(c.Type),
+	}
+
+	if c.Purl != "" {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  c.Purl,
+		})
+	}
+
+	comments := make([]string, 0, 2)
+	if c.CommitHash != "" {
+		comments = append(comments, "commit: "+c.CommitHash)
+	}
+	if len(c.Tags) > 0 {
+		comments = append(comments, "tags: "+strings.Join(c.Tags, ", "))
+	}
+	pkg.Comment = strings.Join(comments, "; ")
+
+	return pkg
+}
+
+// WriteSPDX writes the document in the SPDX JSON format.
+func (d Document) WriteSPDX(w io.Writer) error {
+	var doc spdxDocument
+
+	doc.SPDXVersion = spdxVersion
+	doc.DataLicense = "CC0-1.0"
+	doc.SPDXID = spdxDocumentID
+	doc.Name = d.Name
+	doc.DocumentNamespace = fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s", toolName, d.SerialNumber)
+	doc.CreationInfo.Created = d.Timestamp.UTC().Format(time.RFC3339)
+	doc.CreationInfo.Creators = []string{"Tool: " + toolName}
+
+	components := d.Components()
+
+	// SPDX IDs are limited to letters, numbers, '.', and '-'. use sequential IDs instead of BomRefs.
+	ids := make(map[string]string, len(components))
+	for i, c := range components {
+		ids[c.BomRef] = fmt.Sprintf("SPDXRef-%s-%d", c.Type, i+1)
+	}
+
+	doc.Packages = make([]spdxPackage, 0, len(components)+1)
+	doc.Packages = append(doc.Packages, spdxPackage{
+		SPDXID:                spdxRootID,
+		Name:                  d.Name,
+		DownloadLocation:      spdxNoAssertion,
+		PrimaryPackagePurpose: "APPLICATION",
+	})
+
+	doc.Relationships = []spdxRelationship{
+		{
+			SpdxElementID:      spdxDocumentID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSpdxElement: spdxRootID,
+		},
+	}
+	for _, ref := range d.Roots() {
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SpdxElementID:      spdxRootID,
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSpdxElement: ids[ref],
+		})
+	}
+
+	for _, c := range components {
+		doc.Packages = append(doc.Packages, toSPDXPackage(ids[c.BomRef], c))
+
+		for _, ref := range d.DependsOn(c.BomRef) {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SpdxElementID:      ids[c.BomRef],
+				RelationshipType:   "DEPENDS_ON",
+				RelatedSpdxElement: ids[ref],
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
@@ -0,0 +1,81 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ActionMetadata represents a subset of an action metadata file (action.yml).
+// ref: https://docs.github.com/en/actions/sharing-automations/creating-actions/metadata-syntax-for-github-actions
+type ActionMetadata struct {
+	Name string `yaml:"name"`
+	Runs struct {
+		// Using is a runtime of the action: e.g. node20, docker, composite
+		Using string `yaml:"using"`
+
+		// Image is a Docker image of a Docker container action.
+		Image string `yaml:"image"`
+
+		// Steps is a list of steps of a composite action.
+		Steps []struct {
+			Uses string `yaml:"uses"`
+		} `yaml:"steps"`
+	} `yaml:"runs"`
+}
+
+// IsComposite returns true if the action is a composite action.
+func (m ActionMetadata) IsComposite() bool {
+	return m.Runs.Using == "composite"
+}
+
+// ParseActionMetadata parses the content of an action metadata file.
+func ParseActionMetadata(data []byte) (*ActionMetadata, error) {
+	var metadata ActionMetadata
+
+	if err := yaml.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the action metadata: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+// ReadActionMetadata reads an action metadata file (action.yml or action.yaml) in a directory of a file system.
+// It returns an error that wraps fs.ErrNotExist when the directory has no metadata file.
+func ReadActionMetadata(fsys fs.FS, dir string) (*ActionMetadata, error) {
+	return ReadActionMetadataFunc(func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}, dir)
+}
+
+// ReadActionMetadataFunc reads an action metadata file (action.yml or action.yaml) in a directory with readFile,
+// which reads a file of a slash-separated path. readFile must return an error that wraps fs.ErrNotExist
+// when the file does not exist.
+func ReadActionMetadataFunc(readFile func(name string) ([]byte, error), dir string) (*ActionMetadata, error) {
+	var availableFileNames = []string{"action.yml", "action.yaml"}
+
+	for _, fileName := range availableFileNames {
+		filePath := path.Join(dir, fileName)
+
+		data, err := readFile(filePath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to read an action metadata file: path=%s, error=%w", filePath, err)
+		}
+
+		metadata, err := ParseActionMetadata(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: path=%s", err, filePath)
+		}
+
+		return metadata, nil
+	}
+
+	return nil, &fs.PathError{Op: "read", Path: path.Join(dir, "action.yml"), Err: fs.ErrNotExist}
+}
@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadActionMetadata(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	fsys := fstest.MapFS{
+		"composite/action.yml": {Data: []byte(`name: composite
+runs:
+  using: composite
+  steps:
+    - uses: actions/checkout@v4
+    - run: echo hello
+      shell: bash
+`)},
+		"docker/action.yaml": {Data: []byte(`name: docker
+runs:
+  using: docker
+  image: docker://alpine:3.8
+`)},
+	}
+
+	metadata, err := ReadActionMetadata(fsys, "composite")
+	r.NoError(err)
+	a.True(metadata.IsComposite())
+	r.Len(metadata.Runs.Steps, 2)
+	a.Equal("actions/checkout@v4", metadata.Runs.Steps[0].Uses)
+
+	metadata, err = ReadActionMetadata(fsys, "docker")
+	r.NoError(err)
+	a.False(metadata.IsComposite())
+	a.Equal("docker://alpine:3.8", metadata.Runs.Image)
+
+	_, err = ReadActionMetadata(fsys, "not-found")
+	a.ErrorIs(err, fs.ErrNotExist)
+}
@@ -14,8 +14,10 @@ import (
 type ConfigSource string
 
 const (
-	ConfigSourceFile ConfigSource = "file"
-	ConfigSourceFS   ConfigSource = "fs"
+	ConfigSourceFile   ConfigSource = "file"
+	ConfigSourceFS     ConfigSource = "fs"
+	ConfigSourceGitRev ConfigSource = "git-rev"
+	ConfigSourceRemote ConfigSource = "remote"
 )
 
 var ErrConfigFileNotFound = fmt.Errorf("config file not found")
@@ -25,6 +27,12 @@ type ActionArmorConfigFile struct {
 	fileSystem fs.FS
 	dirPath    string
 	fileName   string
+
+	// origin identifies the file system of the config file, such as a repository and a revision.
+	origin string
+
+	// content is the body of the config file that was read in advance.
+	content []byte
 }
 
 func NewConfigFileFromFile(path string) *ActionArmorConfigFile {
@@ -47,6 +55,32 @@ func NewConfigFileFromFS(fs fs.FS) *ActionArmorConfigFile {
 	}
 }
 
+// NewConfigFileFromGitRev creates a config file that is read from Git objects at a revision.
+// origin is an identifier of the repository and the revision (e.g. /path/to/repo@HEAD~3).
+// path is a slash-separated path to the config file from the repository root.
+func NewConfigFileFromGitRev(fs fs.FS, origin, path string) *ActionArmorConfigFile {
+	return &ActionArmorConfigFile{
+		source:     ConfigSourceGitRev,
+		fileSystem: fs,
+		dirPath:    filepath.Dir(path),
+		fileName:   filepath.Base(path),
+		origin:     origin,
+	}
+}
+
+// NewConfigFileFromContent creates a config file from the content that was read in advance,
+// such as a config file fetched from a remote repository.
+// origin is an identifier of the repository and the ref (e.g. owner/repo@main).
+func NewConfigFileFromContent(content []byte, origin, path string) *ActionArmorConfigFile {
+	return &ActionArmorConfigFile{
+		source:   ConfigSourceRemote,
+		dirPath:  filepath.Dir(path),
+		fileName: filepath.Base(path),
+		origin:   origin,
+		content:  content,
+	}
+}
+
 func (c ActionArmorConfigFile) DirPath() string {
 	return c.dirPath
 }
@@ -62,13 +96,18 @@ func (c ActionArmorConfigFile) FilePath() string {
 // Hash returns a hash value of the instance.
 // Note that the hash value is calculated based on the file path and the source of the instance and it is not a hash of the file content.
 func (c ActionArmorConfigFile) Hash() string {
-	hash := sha3.Sum256([]byte(string(c.source) + c.FilePath()))
+	hash := sha3.Sum256([]byte(string(c.source) + c.origin + c.FilePath()))
 
 	return fmt.Sprintf("%x", hash)
 }
 
 func (c ActionArmorConfigFile) ReadFile() ([]byte, error) {
-	return fs.ReadFile(c.fileSystem, c.FilePath())
+	if c.content != nil {
+		return c.content, nil
+	}
+
+	// fs.FS requires slash-separated paths
+	return fs.ReadFile(c.fileSystem, filepath.ToSlash(c.FilePath()))
 }
 
 func GetConfigFile(proj *actionlint.Project) (*ActionArmorConfigFile, error) {
@@ -0,0 +1,170 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/rhysd/actionlint"
+	"github.com/thombashi/gh-actionarmor/pkg/git"
+	"github.com/thombashi/go-gitexec"
+)
+
+// PosSet is a set of positions in a workflow file.
+type PosSet map[actionlint.Pos]struct{}
+
+// Contains returns true if the set contains the position.
+func (s PosSet) Contains(pos *actionlint.Pos) bool {
+	if pos == nil {
+		return false
+	}
+
+	_, exist := s[*pos]
+	return exist
+}
+
+// collectJobUses returns a mapping of job IDs to 'uses' values of the steps of the job.
+// 'uses' values of reusable workflow calls are not included since they are not linted.
+func collectJobUses(wf *actionlint.Workflow) map[string][]*actionlint.String {
+	jobUses := make(map[string][]*actionlint.String, len(wf.Jobs))
+
+	for jobID, job := range wf.Jobs {
+		uses := make([]*actionlint.String, 0, len(job.Steps))
+
+		for _, step := range job.Steps {
+			if exec, ok := step.Exec.(*actionlint.ExecAction); ok && exec.Uses != nil {
+				uses = append(uses, exec.Uses)
+			}
+		}
+
+		jobUses[jobID] = uses
+	}
+
+	return jobUses
+}
+
+// DiffUses returns positions of 'uses' values in the head workflow that were added or modified from the base workflow.
+//
+// 'uses' values are compared per job: a 'uses' value is treated as unchanged when the job of the same ID
+// in the base workflow has the same 'uses' value. Reordering steps is not treated as a change.
+func DiffUses(base, head []byte) (PosSet, error) {
+	headWorkflow, errs := actionlint.Parse(head)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to parse the head workflow: %s", errs[0].Error())
+	}
+
+	baseJobUses := map[string][]*actionlint.String{}
+	if base != nil {
+		baseWorkflow, errs := actionlint.Parse(base)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("failed to parse the base workflow: %s", errs[0].Error())
+		}
+
+		baseJobUses = collectJobUses(baseWorkflow)
+	}
+
+	changed := PosSet{}
+
+	for jobID, headUses := range collectJobUses(headWorkflow) {
+		baseValues := map[string]struct{}{}
+		for _, uses := range baseJobUses[jobID] {
+			baseValues[uses.Value] = struct{}{}
+		}
+
+		for _, uses := range headUses {
+			if _, exist := baseValues[uses.Value]; exist {
+				continue
+			}
+
+			changed[*uses.Pos] = struct{}{}
+		}
+	}
+
+	return changed, nil
+}
+
+// errNoLocalRepository is returned if a workflow does not belong to a local repository:
+// e.g. a workflow read from the standard input or a workflow of a remote repository.
+var errNoLocalRepository = errors.New("the workflow does not belong to any local repository")
+
+func workflowRepoPath(wfInfo *WorkflowInfo) (string, string, error) {
+	if wfInfo.Rev != "" && wfInfo.RepoDir != "" {
+		return wfInfo.RepoDir, wfInfo.FilePath, nil
+	}
+
+	if wfInfo.Rev != "" || wfInfo.Project == nil {
+		return "", "", fmt.Errorf("%w: %s", errNoLocalRepository, wfInfo.FilePath)
+	}
+
+	relPath, err := filepath.Rel(wfInfo.Project.RootDir(), wfInfo.FilePath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get relative path: %w", err)
+	}
+
+	return wfInfo.Project.RootDir(), filepath.ToSlash(relPath), nil
+}
+
+// SetDiffBase sets the TargetUses of each workflow to the 'uses' values that were added or modified
+// relative to the base revision. A workflow that does not exist at the base revision is linted entirely.
+// Workflows that do not belong to a local repository (e.g. the standard input or remote repositories)
+// have no base revision to compare with, and are linted entirely.
+func SetDiffBase(executor gitexec.GitExecutor, wfInfoList []*WorkflowInfo, baseRev string, logger *slog.Logger) error {
+	baseCommits := map[string]string{}
+
+	for _, wfInfo := range wfInfoList {
+		repoDir, relPath, err := workflowRepoPath(wfInfo)
+		if err != nil {
+			if errors.Is(err, errNoLocalRepository) {
+				logger.Warn("lint the workflow entirely: no local repository to compare with the base revision",
+					slog.String("path", wfInfo.FilePath))
+				continue
+			}
+
+			return err
+		}
+
+		baseCommit, exist := baseCommits[repoDir]
+		if !exist {
+			baseCommit, err = git.ResolveRev(executor, repoDir, baseRev)
+			if err != nil {
+				return fmt.Errorf("failed to resolve the base revision: %w", err)
+			}
+
+			baseCommits[repoDir] = baseCommit
+		}
+
+		head := wfInfo.Content
+		if head == nil {
+			head, err = os.ReadFile(wfInfo.FilePath)
+			if err != nil {
+				return fmt.Errorf("failed to read a workflow file: path=%s, error=%w", wfInfo.FilePath, err)
+			}
+		}
+
+		base, err := git.NewRevFS(executor, repoDir, baseCommit).ReadFile(relPath)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to read a workflow file at the base revision: %w", err)
+			}
+
+			logger.Debug("the workflow does not exist at the base revision", slog.String("path", relPath), slog.String("base", baseRev))
+			base = nil
+		}
+
+		targetUses, err := DiffUses(base, head)
+		if err != nil {
+			// lint entirely: the linter reports the parse error of the head workflow
+			logger.Warn("failed to compute differences", slog.String("path", relPath), slog.Any("error", err))
+			continue
+		}
+
+		logger.Debug("changed 'uses' found", slog.String("path", relPath), slog.String("base", baseRev), slog.Int("count", len(targetUses)))
+
+		wfInfo.TargetUses = targetUses
+	}
+
+	return nil
+}
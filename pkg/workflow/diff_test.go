@@ -0,0 +1,156 @@
+package workflow
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/lithammer/dedent"
+	"github.com/rhysd/actionlint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thombashi/go-gitexec"
+)
+
+func TestDiffUses(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	base := []byte(dedent.Dedent(`
+		on: push
+		jobs:
+		  test:
+		    runs-on: ubuntu-latest
+		    steps:
+		      - uses: actions/checkout@v4
+		      - uses: actions/setup-go@v5
+		`))
+
+	testCases := []struct {
+		name    string
+		base    []byte
+		head    []byte
+		want    PosSet
+		wantErr bool
+	}{
+		{
+			name: "unchanged",
+			base: base,
+			head: base,
+			want: PosSet{},
+		},
+		{
+			name: "modified ref and reordered steps",
+			base: base,
+			head: []byte(dedent.Dedent(`
+				on: push
+				jobs:
+				  test:
+				    runs-on: ubuntu-latest
+				    steps:
+				      - uses: actions/setup-go@v6
+				      - uses: actions/checkout@v4
+				`)),
+			want: PosSet{
+				{Line: 7, Col: 15}: {},
+			},
+		},
+		{
+			name: "added job",
+			base: base,
+			head: []byte(dedent.Dedent(`
+				on: push
+				jobs:
+				  test:
+				    runs-on: ubuntu-latest
+				    steps:
+				      - uses: actions/checkout@v4
+				      - uses: actions/setup-go@v5
+				  lint:
+				    runs-on: ubuntu-latest
+				    steps:
+				      - uses: actions/checkout@v4
+				`)),
+			want: PosSet{
+				{Line: 12, Col: 15}: {},
+			},
+		},
+		{
+			name: "reusable workflow calls are not linted",
+			base: base,
+			head: []byte(dedent.Dedent(`
+				on: push
+				jobs:
+				  test:
+				    runs-on: ubuntu-latest
+				    steps:
+				      - uses: actions/checkout@v4
+				      - uses: actions/setup-go@v5
+				  call:
+				    uses: owner/repo/.github/workflows/reusable.yml@v1
+				`)),
+			want: PosSet{},
+		},
+		{
+			name: "new workflow",
+			base: nil,
+			head: base,
+			want: PosSet{
+				{Line: 7, Col: 15}: {},
+				{Line: 8, Col: 15}: {},
+			},
+		},
+		{
+			name:    "invalid head",
+			base:    base,
+			head:    []byte(``),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		got, err := DiffUses(tc.base, tc.head)
+		if tc.wantErr {
+			r.Error(err, tc.name)
+			continue
+		}
+
+		r.NoError(err, tc.name)
+		a.Equal(tc.want, got, tc.name)
+	}
+}
+
+func TestPosSet_Contains(t *testing.T) {
+	a := assert.New(t)
+
+	s := PosSet{
+		{Line: 1, Col: 2}: {},
+	}
+
+	a.True(s.Contains(&actionlint.Pos{Line: 1, Col: 2}))
+	a.False(s.Contains(&actionlint.Pos{Line: 2, Col: 1}))
+	a.False(s.Contains(nil))
+}
+
+func TestSetDiffBase_NoLocalRepository(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	executor, err := gitexec.New(&gitexec.Params{})
+	r.NoError(err)
+
+	content := []byte("on: push\n")
+	wfInfoList := []*WorkflowInfo{
+		// standard input
+		{FilePath: StdinFilename, Content: content},
+		// remote repository
+		{FilePath: ".github/workflows/ci.yaml", Content: content, RepoID: "octocat/hello-world", Rev: "main"},
+	}
+
+	r.NoError(SetDiffBase(executor, wfInfoList, "HEAD~1", slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	// linted entirely
+	for _, wfInfo := range wfInfoList {
+		a.Nil(wfInfo.TargetUses, wfInfo.FilePath)
+	}
+}
@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+func isGitRepository(dirPath string) bool {
+	// note: .git may be a file for worktrees and submodules
+	_, err := os.Stat(filepath.Join(dirPath, ".git"))
+	return err == nil
+}
+
+func hasWorkflowsDir(dirPath string) bool {
+	fi, err := os.Stat(filepath.Join(dirPath, ".github", "workflows"))
+	return err == nil && fi.IsDir()
+}
+
+// FindRepositories returns paths of Git repositories that have a workflows directory beneath the given directories.
+//
+// Directories are searched recursively until a Git repository is found.
+// Repositories nested in another repository (e.g. submodules) are not searched.
+func FindRepositories(paths []string, logger *slog.Logger) ([]string, error) {
+	repoPaths := make([]string, 0)
+
+	for _, root := range paths {
+		logger.Debug("searching repositories", slog.String("path", root))
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path != root && os.IsPermission(err) {
+					logger.Debug("skip a directory", slog.String("path", path), slog.Any("error", err))
+					return fs.SkipDir
+				}
+
+				return err
+			}
+
+			if !d.IsDir() {
+				return nil
+			}
+
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+
+			if !isGitRepository(path) {
+				return nil
+			}
+
+			if hasWorkflowsDir(path) {
+				logger.Debug("repository found", slog.String("path", path))
+				repoPaths = append(repoPaths, path)
+			} else {
+				logger.Debug("skip a repository", slog.String("path", path), slog.String("reason", "no workflows directory"))
+			}
+
+			return fs.SkipDir
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search repositories: path=%s, error=%w", root, err)
+		}
+	}
+
+	slices.Sort(repoPaths)
+
+	return slices.Compact(repoPaths), nil
+}
@@ -0,0 +1,43 @@
+package workflow
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRepositories(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	root := t.TempDir()
+	mkdir := func(elem ...string) {
+		r.NoError(os.MkdirAll(filepath.Join(append([]string{root}, elem...)...), 0755))
+	}
+
+	// repository with workflows
+	mkdir("org", "repo-a", ".git")
+	mkdir("org", "repo-a", ".github", "workflows")
+	// nested repository is not searched
+	mkdir("org", "repo-a", "nested", ".git")
+	mkdir("org", "repo-a", "nested", ".github", "workflows")
+	// repository without workflows
+	mkdir("org", "repo-b", ".git")
+	// deeper repository with a .git file (e.g. worktree)
+	mkdir("org", "group", "repo-c", ".github", "workflows")
+	r.NoError(os.WriteFile(filepath.Join(root, "org", "group", "repo-c", ".git"), []byte("gitdir: /path/to/git"), 0644))
+	// not a repository
+	mkdir("org", "not-repo", ".github", "workflows")
+
+	got, err := FindRepositories([]string{root, filepath.Join(root, "org")}, slog.Default())
+	r.NoError(err)
+
+	a.Equal([]string{
+		filepath.Join(root, "org", "group", "repo-c"),
+		filepath.Join(root, "org", "repo-a"),
+	}, got)
+}
@@ -0,0 +1,172 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/shurcooL/githubv4"
+)
+
+const maxPageSize = 100
+
+type gitHubSource struct {
+	client *api.GraphQLClient
+}
+
+// NewGitHubSource creates a Source that reads files of repositories through the GitHub GraphQL API.
+func NewGitHubSource(client *api.GraphQLClient) Source {
+	return &gitHubSource{
+		client: client,
+	}
+}
+
+func splitRepoID(repoID string) (string, string, error) {
+	owner, name, found := strings.Cut(repoID, "/")
+	if !found {
+		return "", "", fmt.Errorf("invalid repository ID: expected=OWNER/NAME, actual=%s", repoID)
+	}
+
+	return owner, name, nil
+}
+
+func toObjectExpression(ref, path string) string {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	return fmt.Sprintf("%s:%s", ref, path)
+}
+
+// ListRepositoriesContext returns repository IDs (OWNER/NAME) of an organization. Archived repositories are excluded.
+func (s gitHubSource) ListRepositoriesContext(ctx context.Context, org string) ([]string, error) {
+	var query struct {
+		Organization struct {
+			Repositories struct {
+				Nodes []struct {
+					NameWithOwner string
+					IsArchived    bool
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			} `graphql:"repositories(first: $first, after: $after)"`
+		} `graphql:"organization(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login": githubv4.String(org),
+		"first": githubv4.Int(maxPageSize),
+		"after": (*githubv4.String)(nil),
+	}
+
+	repoIDs := make([]string, 0)
+	for {
+		if err := s.client.QueryWithContext(ctx, "org_repositories", &query, variables); err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+
+		for _, node := range query.Organization.Repositories.Nodes {
+			if node.IsArchived {
+				continue
+			}
+
+			repoIDs = append(repoIDs, node.NameWithOwner)
+		}
+
+		pageInfo := query.Organization.Repositories.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+
+		variables["after"] = githubv4.NewString(githubv4.String(pageInfo.EndCursor))
+	}
+
+	return repoIDs, nil
+}
+
+// ListFilesContext returns slash-separated paths of the files in a directory of a repository at a ref.
+func (s gitHubSource) ListFilesContext(ctx context.Context, repoID, ref, dir string) ([]string, error) {
+	owner, name, err := splitRepoID(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var query struct {
+		Repository struct {
+			Object struct {
+				Tree struct {
+					Entries []struct {
+						Name string
+						Type string
+					}
+				} `graphql:"... on Tree"`
+			} `graphql:"object(expression: $expression)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	dir = strings.TrimSuffix(dir, "/")
+	variables := map[string]interface{}{
+		"owner":      githubv4.String(owner),
+		"name":       githubv4.String(name),
+		"expression": githubv4.String(toObjectExpression(ref, dir)),
+	}
+
+	if err := s.client.QueryWithContext(ctx, "repository_tree", &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to list files: repo=%s, dir=%s, error=%w", repoID, dir, err)
+	}
+
+	paths := make([]string, 0, len(query.Repository.Object.Tree.Entries))
+	for _, entry := range query.Repository.Object.Tree.Entries {
+		if entry.Type != "blob" {
+			continue
+		}
+
+		paths = append(paths, path.Join(dir, entry.Name))
+	}
+
+	return paths, nil
+}
+
+// ReadFileContext reads a file of a repository at a ref.
+func (s gitHubSource) ReadFileContext(ctx context.Context, repoID, ref, filePath string) ([]byte, error) {
+	owner, name, err := splitRepoID(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var query struct {
+		Repository struct {
+			Object *struct {
+				Blob struct {
+					Text     *string
+					IsBinary bool
+				} `graphql:"... on Blob"`
+			} `graphql:"object(expression: $expression)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":      githubv4.String(owner),
+		"name":       githubv4.String(name),
+		"expression": githubv4.String(toObjectExpression(ref, filePath)),
+	}
+
+	if err := s.client.QueryWithContext(ctx, "repository_blob", &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to read a file: repo=%s, path=%s, error=%w", repoID, filePath, err)
+	}
+
+	obj := query.Repository.Object
+	if obj == nil || obj.Blob.Text == nil {
+		return nil, &fs.PathError{Op: "read", Path: fmt.Sprintf("%s:%s", repoID, filePath), Err: fs.ErrNotExist}
+	}
+	if obj.Blob.IsBinary {
+		return nil, fmt.Errorf("binary file is not supported: repo=%s, path=%s", repoID, filePath)
+	}
+
+	return []byte(*obj.Blob.Text), nil
+}
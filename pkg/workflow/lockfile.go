@@ -0,0 +1,196 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	lockfileVersion = 2
+
+	// legacyLockfileVersion is a version of lockfiles whose keys do not include hosts: OWNER/REPO@TAG
+	legacyLockfileVersion = 1
+)
+
+// LockfilePath is a slash-separated path to the lockfile from the repository root.
+var LockfilePath = path.Join(".github", common.ToolName+".lock")
+
+const lockfileHeader = `# This file is generated by gh-%s. Do not edit it manually.
+# Run 'gh %s lock update' to update the file.
+`
+
+// Lockfile represents a lockfile that records commit hashes that tag references of actions resolved to.
+type Lockfile struct {
+	Version int `yaml:"version"`
+
+	// Actions is a mapping of HOST/OWNER/REPO@TAG to a commit hash.
+	Actions map[string]string `yaml:"actions"`
+}
+
+// LockfileChange represents a change of a lockfile entry.
+type LockfileChange struct {
+	// Key is a key of the entry: HOST/OWNER/REPO@TAG
+	Key string
+
+	// Old is a commit hash before the change. It is empty when the entry is added.
+	Old string
+
+	// New is a commit hash after the change. It is empty when the entry is removed.
+	New string
+}
+
+// NewLockfile creates an empty Lockfile.
+func NewLockfile() *Lockfile {
+	return &Lockfile{
+		Version: lockfileVersion,
+		Actions: map[string]string{},
+	}
+}
+
+// LockKey returns a key of a lockfile entry: HOST/OWNER/REPO@TAG
+func LockKey(host, repoID, tag string) string {
+	return fmt.Sprintf("%s/%s@%s", host, repoID, tag)
+}
+
+// ParseLockfile parses the content of a lockfile.
+func ParseLockfile(data []byte) (*Lockfile, error) {
+	lockfile := NewLockfile()
+
+	if err := yaml.Unmarshal(data, lockfile); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the lockfile: %w", err)
+	}
+
+	if lockfile.Version != lockfileVersion && lockfile.Version != legacyLockfileVersion {
+		return nil, fmt.Errorf("unsupported lockfile version: expected=%d, actual=%d", lockfileVersion, lockfile.Version)
+	}
+
+	if lockfile.Actions == nil {
+		lockfile.Actions = map[string]string{}
+	}
+
+	return lockfile, nil
+}
+
+// ReadLockfile reads the lockfile of a repository from a file system of the repository root.
+// It returns an error that wraps fs.ErrNotExist when the repository has no lockfile.
+func ReadLockfile(fsys fs.FS) (*Lockfile, error) {
+	data, err := fs.ReadFile(fsys, LockfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lockfile, err := ParseLockfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: path=%s", err, LockfilePath)
+	}
+
+	return lockfile, nil
+}
+
+// findLockfile returns the lockfile of a repository. It returns nil if the repository has no lockfile.
+func findLockfile(fsys fs.FS, logger *slog.Logger) (*Lockfile, error) {
+	lockfile, err := ReadLockfile(fsys)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			logger.Debug("lockfile not found", slog.String("path", LockfilePath))
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read a lockfile: %w", err)
+	}
+
+	return lockfile, nil
+}
+
+// Lookup returns a locked commit hash of a tag reference.
+// Entries of a lockfile of the legacy version are looked up without the host.
+func (l Lockfile) Lookup(host, repoID, tag string) (string, bool) {
+	if l.Version == legacyLockfileVersion {
+		sha, exist := l.Actions[fmt.Sprintf("%s@%s", repoID, tag)]
+
+		return sha, exist
+	}
+
+	sha, exist := l.Actions[LockKey(host, repoID, tag)]
+
+	return sha, exist
+}
+
+// Set records a commit hash of a tag reference.
+func (l *Lockfile) Set(host, repoID, tag, sha string) {
+	l.Actions[LockKey(host, repoID, tag)] = sha
+}
+
+// Diff returns changes from a base lockfile to the lockfile sorted by the keys.
+// A nil base is treated as an empty lockfile.
+func (l Lockfile) Diff(base *Lockfile) []LockfileChange {
+	if base == nil {
+		base = NewLockfile()
+	}
+
+	changes := make([]LockfileChange, 0)
+
+	for key, sha := range l.Actions {
+		if old := base.Actions[key]; old != sha {
+			changes = append(changes, LockfileChange{Key: key, Old: old, New: sha})
+		}
+	}
+
+	for key, old := range base.Actions {
+		if _, exist := l.Actions[key]; !exist {
+			changes = append(changes, LockfileChange{Key: key, Old: old})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b LockfileChange) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return changes
+}
+
+// Marshal returns the content of the lockfile.
+func (l Lockfile) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, lockfileHeader, common.ToolName, common.ToolName)
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(l); err != nil {
+		return nil, fmt.Errorf("failed to marshal the lockfile: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal the lockfile: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteFile writes the lockfile to a repository.
+func (l Lockfile) WriteFile(rootDir string) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(rootDir, filepath.FromSlash(LockfilePath))
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write the lockfile: path=%s, error=%w", filePath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package workflow
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSHA1 = "11bd71901bbe5b1630ceea73d27597364c9af683"
+	testSHA2 = "0565240e2d4ab88bba5387d719585280857ece09"
+)
+
+func TestLockfile_WriteFile(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	rootDir := t.TempDir()
+	r.NoError(os.MkdirAll(filepath.Join(rootDir, ".github"), 0755))
+
+	lockfile := NewLockfile()
+	lockfile.Set("github.com", "actions/checkout", "v4", testSHA1)
+	lockfile.Set("github.com", "docker/build-push-action", "v6", testSHA2)
+	r.NoError(lockfile.WriteFile(rootDir))
+
+	got, err := ReadLockfile(os.DirFS(rootDir))
+	r.NoError(err)
+	a.Equal(lockfile, got)
+
+	sha, exist := got.Lookup("github.com", "actions/checkout", "v4")
+	a.True(exist)
+	a.Equal(testSHA1, sha)
+
+	_, exist = got.Lookup("github.com", "actions/checkout", "v3")
+	a.False(exist)
+
+	// the same repository on another host is not locked
+	_, exist = got.Lookup("ghe.example.com", "actions/checkout", "v4")
+	a.False(exist)
+
+	_, err = ReadLockfile(fstest.MapFS{})
+	a.ErrorIs(err, fs.ErrNotExist)
+
+	_, err = ReadLockfile(fstest.MapFS{LockfilePath: {Data: []byte("version: 3\n")}})
+	a.Error(err)
+}
+
+func TestLockfile_Diff(t *testing.T) {
+	a := assert.New(t)
+
+	base := NewLockfile()
+	base.Set("github.com", "actions/checkout", "v4", testSHA1)
+	base.Set("github.com", "actions/setup-go", "v5", testSHA1)
+	base.Set("github.com", "docker/build-push-action", "v6", testSHA1)
+
+	lockfile := NewLockfile()
+	lockfile.Set("github.com", "actions/cache", "v4", testSHA2)
+	lockfile.Set("github.com", "actions/checkout", "v4", testSHA1)
+	lockfile.Set("github.com", "docker/build-push-action", "v6", testSHA2)
+
+	a.Equal([]LockfileChange{
+		{Key: "github.com/actions/cache@v4", New: testSHA2},
+		{Key: "github.com/actions/setup-go@v5", Old: testSHA1},
+		{Key: "github.com/docker/build-push-action@v6", Old: testSHA1, New: testSHA2},
+	}, lockfile.Diff(base))
+
+	a.Len(lockfile.Diff(nil), 3)
+	a.Empty(lockfile.Diff(lockfile))
+}
+
+func TestParseLockfile_LegacyVersion(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	lockfile, err := ParseLockfile([]byte("version: 1\nactions:\n  actions/checkout@v4: " + testSHA1 + "\n"))
+	r.NoError(err)
+
+	sha, exist := lockfile.Lookup("github.com", "actions/checkout", "v4")
+	a.True(exist)
+	a.Equal(testSHA1, sha)
+}
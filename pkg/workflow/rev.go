@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+	"github.com/thombashi/gh-actionarmor/pkg/git"
+	"github.com/thombashi/go-gitexec"
+)
+
+const workflowsDirPath = ".github/workflows"
+
+func getConfigFileAtRev(revFS *git.RevFS) (*ActionArmorConfigFile, error) {
+	var availableFileExtensions = []string{".yaml", ".yml"}
+
+	origin := fmt.Sprintf("%s@%s", revFS.DirPath(), revFS.Rev())
+
+	for _, ext := range availableFileExtensions {
+		configFilePath := path.Join(".github", common.ToolName+ext)
+
+		_, err := revFS.ReadFile(configFilePath)
+		if err == nil {
+			return NewConfigFileFromGitRev(revFS, origin, configFilePath), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read a config file: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: path=%s:.github", ErrConfigFileNotFound, origin)
+}
+
+// ListWorkflowsAtRev returns a list of WorkflowInfo of Git repositories at a revision.
+//
+// The workflow files and the config files are read from Git objects without checking out the revision.
+// The paths can be either working trees or bare repositories.
+func ListWorkflowsAtRev(executor gitexec.GitExecutor, paths []string, rev string, logger *slog.Logger) ([]*WorkflowInfo, error) {
+	workflows := make([]*WorkflowInfo, 0)
+
+	for _, dirPath := range paths {
+		isFile, err := common.IsFile(dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if the argument is a file: %w", err)
+		}
+		if isFile {
+			return nil, fmt.Errorf("a path must be a directory of a Git repository when a revision is specified: %s", dirPath)
+		}
+
+		commitHash, err := git.ResolveRev(executor, dirPath, rev)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Debug("listing workflows at a revision",
+			slog.String("path", dirPath),
+			slog.String("rev", rev),
+			slog.String("commit", commitHash),
+		)
+
+		revFS := git.NewRevFS(executor, dirPath, commitHash)
+
+		config, err := getConfigFileAtRev(revFS)
+		if err != nil {
+			if !errors.Is(err, ErrConfigFileNotFound) {
+				return nil, err
+			}
+
+			logger.Debug(err.Error())
+		}
+
+		lockfile, err := findLockfile(revFS, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		filePaths, err := revFS.ListFiles(workflowsDirPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, filePath := range filePaths {
+			ext := path.Ext(filePath)
+			if ext != ".yaml" && ext != ".yml" {
+				continue
+			}
+
+			content, err := revFS.ReadFile(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read a workflow file: %w", err)
+			}
+
+			logger.Debug("workflow file found", slog.String("path", filePath), slog.String("rev", rev))
+
+			workflows = append(workflows, &WorkflowInfo{
+				FilePath: filePath,
+				Config:   config,
+				Lockfile: lockfile,
+				Content:  content,
+				RepoDir:  dirPath,
+				Rev:      rev,
+			})
+		}
+	}
+
+	return workflows, nil
+}
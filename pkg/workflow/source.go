@@ -0,0 +1,174 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
+)
+
+// Source is an interface to read workflow files and config files of repositories without cloning them.
+type Source interface {
+	// ListRepositoriesContext returns repository IDs (OWNER/NAME) of an organization.
+	ListRepositoriesContext(ctx context.Context, org string) ([]string, error)
+
+	// ListFilesContext returns slash-separated paths of the files in a directory of a repository at a ref.
+	// If the ref is empty, the default branch is used.
+	// It returns an empty list when the directory does not exist.
+	ListFilesContext(ctx context.Context, repoID, ref, dir string) ([]string, error)
+
+	// ReadFileContext reads a file of a repository at a ref.
+	// If the ref is empty, the default branch is used.
+	// It returns an error that wraps fs.ErrNotExist when the file does not exist.
+	ReadFileContext(ctx context.Context, repoID, ref, path string) ([]byte, error)
+}
+
+// RemoteTarget represents a repository to lint through a Source.
+type RemoteTarget struct {
+	// RepoID is a repository ID (OWNER/NAME).
+	RepoID string
+
+	// Ref is a git ref (branch, tag, or commit hash). An empty string means the default branch.
+	Ref string
+}
+
+func (t RemoteTarget) String() string {
+	if t.Ref == "" {
+		return t.RepoID
+	}
+
+	return fmt.Sprintf("%s@%s", t.RepoID, t.Ref)
+}
+
+// ParseRemoteTarget parses a string formatted as OWNER/REPO[@REF].
+func ParseRemoteTarget(s string) (*RemoteTarget, error) {
+	repoID, ref, _ := strings.Cut(strings.TrimSpace(s), "@")
+
+	items := strings.Split(repoID, "/")
+	if len(items) != 2 || items[0] == "" || items[1] == "" {
+		return nil, fmt.Errorf("invalid remote repository: expected=OWNER/REPO[@REF], actual=%s", s)
+	}
+
+	return &RemoteTarget{
+		RepoID: repoID,
+		Ref:    ref,
+	}, nil
+}
+
+// ListOrgTargets returns RemoteTarget of the repositories of the organizations.
+func ListOrgTargets(ctx context.Context, src Source, orgs []string, logger *slog.Logger) ([]*RemoteTarget, error) {
+	targets := make([]*RemoteTarget, 0)
+
+	for _, org := range orgs {
+		repoIDs, err := src.ListRepositoriesContext(ctx, org)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories: org=%s, error=%w", org, err)
+		}
+
+		logger.Debug("repositories found", slog.String("org", org), slog.Int("count", len(repoIDs)))
+
+		for _, repoID := range repoIDs {
+			targets = append(targets, &RemoteTarget{RepoID: repoID})
+		}
+	}
+
+	return targets, nil
+}
+
+func readConfigFileFromSource(ctx context.Context, src Source, target *RemoteTarget) (*ActionArmorConfigFile, error) {
+	var availableFileExtensions = []string{".yaml", ".yml"}
+
+	for _, ext := range availableFileExtensions {
+		configFilePath := path.Join(".github", common.ToolName+ext)
+
+		content, err := src.ReadFileContext(ctx, target.RepoID, target.Ref, configFilePath)
+		if err == nil {
+			return NewConfigFileFromContent(content, target.String(), configFilePath), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read a config file: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: repo=%s", ErrConfigFileNotFound, target)
+}
+
+func readLockfileFromSource(ctx context.Context, src Source, target *RemoteTarget) (*Lockfile, error) {
+	content, err := src.ReadFileContext(ctx, target.RepoID, target.Ref, LockfilePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read a lockfile: %w", err)
+	}
+
+	lockfile, err := ParseLockfile(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: repo=%s", err, target)
+	}
+
+	return lockfile, nil
+}
+
+// ListWorkflowsFromSource returns a list of WorkflowInfo of the repositories read through a Source.
+func ListWorkflowsFromSource(ctx context.Context, src Source, targets []*RemoteTarget, logger *slog.Logger) ([]*WorkflowInfo, error) {
+	workflows := make([]*WorkflowInfo, 0)
+
+	for _, target := range targets {
+		logger.Debug("listing workflows of a remote repository", slog.String("repo", target.String()))
+
+		filePaths, err := src.ListFilesContext(ctx, target.RepoID, target.Ref, workflowsDirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow files: repo=%s, error=%w", target, err)
+		}
+		if len(filePaths) == 0 {
+			logger.Debug("skip a repository", slog.String("repo", target.String()), slog.String("reason", "no workflow files"))
+			continue
+		}
+
+		config, err := readConfigFileFromSource(ctx, src, target)
+		if err != nil {
+			if !errors.Is(err, ErrConfigFileNotFound) {
+				return nil, err
+			}
+
+			logger.Debug(err.Error())
+		}
+
+		lockfile, err := readLockfileFromSource(ctx, src, target)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, filePath := range filePaths {
+			ext := path.Ext(filePath)
+			if ext != ".yaml" && ext != ".yml" {
+				continue
+			}
+
+			content, err := src.ReadFileContext(ctx, target.RepoID, target.Ref, filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read a workflow file: repo=%s, error=%w", target, err)
+			}
+
+			logger.Debug("workflow file found", slog.String("repo", target.String()), slog.String("path", filePath))
+
+			workflows = append(workflows, &WorkflowInfo{
+				FilePath: filePath,
+				Config:   config,
+				Lockfile: lockfile,
+				Content:  content,
+				RepoID:   target.RepoID,
+				Rev:      target.Ref,
+			})
+		}
+	}
+
+	return workflows, nil
+}
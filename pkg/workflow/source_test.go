@@ -0,0 +1,143 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource is an in-memory Source.
+// files is a mapping of OWNER/NAME@REF to a mapping of file paths to contents.
+type fakeSource struct {
+	orgs  map[string][]string
+	files map[string]map[string]string
+}
+
+func (s fakeSource) key(repoID, ref string) string {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	return repoID + "@" + ref
+}
+
+func (s fakeSource) ListRepositoriesContext(_ context.Context, org string) ([]string, error) {
+	repoIDs, exist := s.orgs[org]
+	if !exist {
+		return nil, fmt.Errorf("organization not found: %s", org)
+	}
+
+	return repoIDs, nil
+}
+
+func (s fakeSource) ListFilesContext(_ context.Context, repoID, ref, dir string) ([]string, error) {
+	paths := make([]string, 0)
+	for p := range s.files[s.key(repoID, ref)] {
+		if path.Dir(p) == strings.TrimSuffix(dir, "/") {
+			paths = append(paths, p)
+		}
+	}
+	slices.Sort(paths)
+
+	return paths, nil
+}
+
+func (s fakeSource) ReadFileContext(_ context.Context, repoID, ref, filePath string) ([]byte, error) {
+	content, exist := s.files[s.key(repoID, ref)][filePath]
+	if !exist {
+		return nil, &fs.PathError{Op: "read", Path: filePath, Err: fs.ErrNotExist}
+	}
+
+	return []byte(content), nil
+}
+
+func TestParseRemoteTarget(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		value   string
+		want    *RemoteTarget
+		wantErr bool
+	}{
+		{value: "owner/repo", want: &RemoteTarget{RepoID: "owner/repo"}},
+		{value: "owner/repo@v1", want: &RemoteTarget{RepoID: "owner/repo", Ref: "v1"}},
+		{value: "owner/repo@refs/pull/1/merge", want: &RemoteTarget{RepoID: "owner/repo", Ref: "refs/pull/1/merge"}},
+		{value: "owner", wantErr: true},
+		{value: "owner/repo/sub", wantErr: true},
+		{value: "/repo", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseRemoteTarget(tc.value)
+		if tc.wantErr {
+			a.Error(err, tc.value)
+			continue
+		}
+
+		a.NoError(err, tc.value)
+		a.Equal(tc.want, got, tc.value)
+	}
+}
+
+func TestListWorkflowsFromSource(t *testing.T) {
+	a := assert.New(t)
+	r := require.New(t)
+
+	src := fakeSource{
+		orgs: map[string][]string{
+			"org": {"org/repo-a", "org/repo-b"},
+		},
+		files: map[string]map[string]string{
+			"org/repo-a@HEAD": {
+				".github/workflows/ci.yaml":     "ci",
+				".github/workflows/release.yml": "release",
+				".github/workflows/README.md":   "readme",
+				".github/actionarmor.yml":       "enforce_pin_hash: true",
+			},
+			"org/repo-b@HEAD": {
+				"README.md": "no workflows",
+			},
+			"other/repo@v1": {
+				".github/workflows/ci.yaml": "ci v1",
+			},
+		},
+	}
+	logger := slog.Default()
+	ctx := context.Background()
+
+	targets, err := ListOrgTargets(ctx, src, []string{"org"}, logger)
+	r.NoError(err)
+	targets = append(targets, &RemoteTarget{RepoID: "other/repo", Ref: "v1"})
+
+	workflows, err := ListWorkflowsFromSource(ctx, src, targets, logger)
+	r.NoError(err)
+	r.Len(workflows, 3)
+
+	a.Equal(".github/workflows/ci.yaml", workflows[0].FilePath)
+	a.Equal("org/repo-a", workflows[0].RepoID)
+	a.Equal("ci", string(workflows[0].Content))
+	a.Empty(workflows[0].Rev)
+	r.NotNil(workflows[0].Config)
+	config, err := workflows[0].Config.ReadFile()
+	r.NoError(err)
+	a.Equal("enforce_pin_hash: true", string(config))
+
+	a.Equal(".github/workflows/release.yml", workflows[1].FilePath)
+
+	a.Equal(".github/workflows/ci.yaml", workflows[2].FilePath)
+	a.Equal("other/repo", workflows[2].RepoID)
+	a.Equal("v1", workflows[2].Rev)
+	a.Equal("ci v1", string(workflows[2].Content))
+	a.Nil(workflows[2].Config)
+
+	_, err = ListOrgTargets(ctx, src, []string{"unknown"}, logger)
+	r.Error(err)
+}
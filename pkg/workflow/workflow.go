@@ -3,6 +3,7 @@ package workflow
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -11,6 +12,12 @@ import (
 	"github.com/thombashi/gh-actionarmor/internal/pkg/common"
 )
 
+// StdinPath is a path argument that represents the standard input.
+const StdinPath = "-"
+
+// StdinFilename is a file path for reporting a workflow read from the standard input without a file path.
+const StdinFilename = "<stdin>"
+
 type WorkflowInfo struct {
 	// FilePath is an absolute path to the GitHub Actions workflow file.
 	FilePath string
@@ -20,6 +27,43 @@ type WorkflowInfo struct {
 
 	// Config is an ActionArmor configuration file.
 	Config *ActionArmorConfigFile
+
+	// Lockfile is a lockfile of the repository. It is nil when the repository has no lockfile.
+	Lockfile *Lockfile
+
+	// Content is the body of the workflow file.
+	// If it is nil, the content is read from the FilePath.
+	Content []byte
+
+	// RepoID is a repository ID (OWNER/NAME) of the workflow.
+	// If it is empty, the repository ID is determined from the Project or the RepoDir.
+	RepoID string
+
+	// RepoDir is a path to the Git repository of the workflow.
+	// It is used instead of the Project when the workflow does not exist in a working tree.
+	RepoDir string
+
+	// Rev is a Git revision that the workflow was read from.
+	// It is empty when the workflow was read from a working tree.
+	Rev string
+
+	// TargetUses is a set of positions of 'uses' values to be linted.
+	// If it is nil, all of the 'uses' values in the workflow are linted.
+	TargetUses PosSet
+}
+
+func findConfigFile(proj *actionlint.Project, logger *slog.Logger) (*ActionArmorConfigFile, error) {
+	config, err := GetConfigFile(proj)
+	if err != nil {
+		if errors.Is(err, ErrConfigFileNotFound) {
+			logger.Debug(err.Error())
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get a config file path: %w", err)
+	}
+
+	return config, nil
 }
 
 func extractWorkflows(dirPath string, logger *slog.Logger) ([]*WorkflowInfo, error) {
@@ -33,13 +77,14 @@ func extractWorkflows(dirPath string, logger *slog.Logger) ([]*WorkflowInfo, err
 		return nil, fmt.Errorf("failed to find project at %s", dirPath)
 	}
 
-	config, err := GetConfigFile(proj)
+	config, err := findConfigFile(proj, logger)
 	if err != nil {
-		if errors.Is(err, ErrConfigFileNotFound) {
-			logger.Debug(err.Error())
-		} else {
-			return nil, fmt.Errorf("failed to get a config file path: %w", err)
-		}
+		return nil, err
+	}
+
+	lockfile, err := findLockfile(os.DirFS(proj.RootDir()), logger)
+	if err != nil {
+		return nil, err
 	}
 
 	logger.Debug("extracting workflow files", slog.String("path", proj.WorkflowsDir()))
@@ -66,13 +111,14 @@ func extractWorkflows(dirPath string, logger *slog.Logger) ([]*WorkflowInfo, err
 			FilePath: workflowFilePath,
 			Project:  proj,
 			Config:   config,
+			Lockfile: lockfile,
 		})
 	}
 
 	return workflows, nil
 }
 
-func toWorkflowInfo(path string) (*WorkflowInfo, error) {
+func toWorkflowInfo(path string, logger *slog.Logger) (*WorkflowInfo, error) {
 	proj, err := actionlint.NewProjects().At(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find project: %w", err)
@@ -86,12 +132,74 @@ func toWorkflowInfo(path string) (*WorkflowInfo, error) {
 		Project:  proj,
 	}
 
-	config, err := GetConfigFile(proj)
-	if err != nil && errors.Is(err, ErrConfigFileNotFound) {
-		return nil, fmt.Errorf("failed to get a config file path: %w", err)
+	config, err := findConfigFile(proj, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow.Config = config
+
+	workflow.Lockfile, err = findLockfile(os.DirFS(proj.RootDir()), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return workflow, nil
+}
+
+// ReadWorkflow reads a workflow content from a reader such as the standard input.
+//
+// The second argument 'filePath' is a path to the workflow file used for reporting.
+// If the file path belongs to a project, the project and its config file are associated with the workflow.
+// No project is associated if the file path is empty or StdinFilename.
+func ReadWorkflow(r io.Reader, filePath string, logger *slog.Logger) (*WorkflowInfo, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read a workflow: %w", err)
+	}
+
+	if filePath == "" {
+		filePath = StdinFilename
+	}
+
+	workflow := &WorkflowInfo{
+		FilePath: filePath,
+		Content:  content,
+	}
+
+	// a project must not be resolved from the current directory for a workflow without a file path
+	if filePath == StdinFilename {
+		return workflow, nil
+	}
+
+	proj, err := actionlint.NewProjects().At(filepath.Dir(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	if proj == nil {
+		logger.Debug("the workflow does not belong to any project", slog.String("path", filePath))
+		return workflow, nil
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the absolute path: %w", err)
+	}
+
+	config, err := findConfigFile(proj, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	lockfile, err := findLockfile(os.DirFS(proj.RootDir()), logger)
+	if err != nil {
+		return nil, err
 	}
 
+	workflow.FilePath = absPath
+	workflow.Project = proj
 	workflow.Config = config
+	workflow.Lockfile = lockfile
 
 	return workflow, nil
 }
@@ -112,7 +220,7 @@ func ListWorkflows(paths []string, logger *slog.Logger) ([]*WorkflowInfo, error)
 			return nil, fmt.Errorf("failed to check if the argument is a file: %w", err)
 		}
 		if isFile {
-			workflow, err := toWorkflowInfo(path)
+			workflow, err := toWorkflowInfo(path, logger)
 			if err != nil {
 				return nil, err
 			}
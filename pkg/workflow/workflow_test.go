@@ -0,0 +1,76 @@
+package workflow
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWorkflow(t *testing.T) {
+	r := require.New(t)
+
+	const content = "on: push\n"
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	root := t.TempDir()
+	r.NoError(os.MkdirAll(filepath.Join(root, ".git"), 0755))
+	r.NoError(os.MkdirAll(filepath.Join(root, ".github", "workflows"), 0755))
+
+	prevWd, err := os.Getwd()
+	r.NoError(err)
+	r.NoError(os.Chdir(root))
+	t.Cleanup(func() {
+		_ = os.Chdir(prevWd)
+	})
+
+	// the working directory may differ from root if root contains symbolic links
+	wd, err := os.Getwd()
+	r.NoError(err)
+
+	testCases := []struct {
+		name        string
+		filePath    string
+		wantPath    string
+		wantProject bool
+	}{
+		{
+			name:        "no file path",
+			filePath:    "",
+			wantPath:    StdinFilename,
+			wantProject: false,
+		},
+		{
+			// the project of the current directory must not be associated
+			name:        "default file path",
+			filePath:    StdinFilename,
+			wantPath:    StdinFilename,
+			wantProject: false,
+		},
+		{
+			name:        "file path in a project",
+			filePath:    filepath.Join(".github", "workflows", "ci.yml"),
+			wantPath:    filepath.Join(wd, ".github", "workflows", "ci.yml"),
+			wantProject: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			r := require.New(t)
+
+			wfInfo, err := ReadWorkflow(strings.NewReader(content), tc.filePath, logger)
+			r.NoError(err)
+
+			a.Equal(content, string(wfInfo.Content))
+			a.Equal(tc.wantProject, wfInfo.Project != nil)
+			a.Equal(tc.wantPath, wfInfo.FilePath)
+		})
+	}
+}